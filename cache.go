@@ -0,0 +1,108 @@
+package dag
+
+import "container/list"
+
+// cacheLRU keeps track of the order in which the entries of an ancestors- or
+// descendants-cache were used (see Options.MaxCacheEntries).
+type cacheLRU struct {
+	order    *list.List
+	elements map[interface{}]*list.Element
+}
+
+func newCacheLRU() *cacheLRU {
+	return &cacheLRU{
+		order:    list.New(),
+		elements: make(map[interface{}]*list.Element),
+	}
+}
+
+// touch marks the entry with the key vHash as most recently used.
+func (c *cacheLRU) touch(vHash interface{}) {
+	if e, exists := c.elements[vHash]; exists {
+		c.order.MoveToFront(e)
+		return
+	}
+	c.elements[vHash] = c.order.PushFront(vHash)
+}
+
+// remove forgets the entry with the key vHash.
+func (c *cacheLRU) remove(vHash interface{}) {
+	if e, exists := c.elements[vHash]; exists {
+		c.order.Remove(e)
+		delete(c.elements, vHash)
+	}
+}
+
+// rename replaces the key oldHash by newHash keeping the position of the entry.
+func (c *cacheLRU) rename(oldHash, newHash interface{}) {
+	if e, exists := c.elements[oldHash]; exists {
+		e.Value = newHash
+		delete(c.elements, oldHash)
+		c.elements[newHash] = e
+	}
+}
+
+// evict deletes the least recently used entries from cache until cache holds
+// at most max entries.
+func (c *cacheLRU) evict(cache map[interface{}]map[interface{}]struct{}, max int) {
+	for len(cache) > max {
+		e := c.order.Back()
+		if e == nil {
+			return
+		}
+		c.order.Remove(e)
+		delete(c.elements, e.Value)
+		delete(cache, e.Value)
+	}
+}
+
+// deleteCache deletes the entry with the key vHash from the given cache and
+// its LRU list.
+func (d *DAG) deleteCache(cache map[interface{}]map[interface{}]struct{}, lru *cacheLRU, vHash interface{}) {
+	delete(cache, vHash)
+	lru.remove(vHash)
+}
+
+// touchCache marks the entry with the key vHash of the given cache as most
+// recently used, iff the size of the caches is limited.
+func (d *DAG) touchCache(lru *cacheLRU, vHash interface{}) {
+	if d.options.MaxCacheEntries <= 0 {
+		return
+	}
+	d.muCache.Lock()
+	lru.touch(vHash)
+	d.muCache.Unlock()
+}
+
+// storeCache stores the relatives of the vertex with the hash vHash in the given
+// cache and evicts the least recently used entries, iff the size of the caches
+// is limited.
+func (d *DAG) storeCache(cache map[interface{}]map[interface{}]struct{}, lru *cacheLRU, vHash interface{}, relatives map[interface{}]struct{}) {
+	d.muCache.Lock()
+	defer d.muCache.Unlock()
+	cache[vHash] = relatives
+	if d.options.MaxCacheEntries > 0 {
+		lru.touch(vHash)
+		lru.evict(cache, d.options.MaxCacheEntries)
+	}
+}
+
+// collectRelatives returns the hashes of all vertices reachable from vHash via
+// the given edges (i.e. the descendants for outboundEdge and the ancestors for
+// inboundEdge) without using or populating the caches (see
+// Options.DisableCaching).
+func (d *DAG) collectRelatives(vHash interface{}, edges map[interface{}]map[interface{}]struct{}) map[interface{}]struct{} {
+	relatives := make(map[interface{}]struct{})
+	fifo := []interface{}{vHash}
+	for len(fifo) > 0 {
+		top := fifo[0]
+		fifo = fifo[1:]
+		for relative := range edges[top] {
+			if _, exists := relatives[relative]; !exists {
+				relatives[relative] = struct{}{}
+				fifo = append(fifo, relative)
+			}
+		}
+	}
+	return relatives
+}
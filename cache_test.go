@@ -0,0 +1,161 @@
+package dag
+
+import (
+	"github.com/go-test/deep"
+	"strconv"
+	"testing"
+)
+
+func TestMaxCacheEntriesOption(t *testing.T) {
+	const n, max = 100, 10
+
+	dag := NewDAG()
+	dag.Options(Options{MaxCacheEntries: max})
+
+	// a chain 0 -> 1 -> ... -> 99 plus a transitive edge 0 -> 2
+	for i := 0; i < n; i++ {
+		_ = dag.AddVertexByID(strconv.Itoa(i), i)
+		if i > 0 {
+			_ = dag.AddEdge(strconv.Itoa(i-1), strconv.Itoa(i))
+		}
+	}
+	_ = dag.AddEdge("0", "2")
+
+	for i := 0; i < n; i++ {
+		id := strconv.Itoa(i)
+		descendants, _ := dag.GetDescendants(id)
+		if len(descendants) != n-1-i {
+			t.Errorf("len(GetDescendants(%s)) = %d, want %d", id, len(descendants), n-1-i)
+		}
+		ancestors, _ := dag.GetAncestors(id)
+		if len(ancestors) != i {
+			t.Errorf("len(GetAncestors(%s)) = %d, want %d", id, len(ancestors), i)
+		}
+		if size := len(dag.descendantsCache); size > max {
+			t.Fatalf("len(descendantsCache) = %d, want at most %d", size, max)
+		}
+		if size := len(dag.ancestorsCache); size > max {
+			t.Fatalf("len(ancestorsCache) = %d, want at most %d", size, max)
+		}
+	}
+
+	// recently used entries are kept
+	_, _ = dag.GetDescendants("50")
+	for i := 0; i < max-1; i++ {
+		_, _ = dag.GetDescendants(strconv.Itoa(n - 1 - i))
+	}
+	vHash := dag.hashVertex(50)
+	if _, exists := dag.descendantsCache[vHash]; !exists {
+		t.Errorf("descendantsCache[50] evicted, want it to be kept")
+	}
+
+	// the reduction doesn't rely on the cache holding the whole closure
+	removed := dag.ReduceTransitivelyRemoved()
+	if want := [][2]string{{"0", "2"}}; deep.Equal(removed, want) != nil {
+		t.Errorf("ReduceTransitivelyRemoved() = %v, want %v", removed, want)
+	}
+	if size := len(dag.descendantsCache); size > max {
+		t.Errorf("len(descendantsCache) = %d, want at most %d", size, max)
+	}
+}
+
+func TestMaxCacheEntriesEdits(t *testing.T) {
+	const n, max = 20, 5
+
+	dag := NewDAG()
+	dag.Options(Options{MaxCacheEntries: max})
+	for i := 0; i < n; i++ {
+		_ = dag.AddVertexByID(strconv.Itoa(i), i)
+	}
+
+	checkLRU := func(step string) {
+		t.Helper()
+		for _, c := range []struct {
+			name  string
+			cache map[interface{}]map[interface{}]struct{}
+			lru   *cacheLRU
+		}{
+			{"ancestors", dag.ancestorsCache, dag.ancestorsLRU},
+			{"descendants", dag.descendantsCache, dag.descendantsLRU},
+		} {
+			if c.lru.order.Len() != len(c.cache) || len(c.lru.elements) != len(c.cache) {
+				t.Fatalf("%s: len(%sLRU) = %d (%d elements), want len(%sCache) = %d", step, c.name, c.lru.order.Len(), len(c.lru.elements), c.name, len(c.cache))
+			}
+		}
+	}
+
+	for round := 0; round < 3; round++ {
+		for i := 1; i < n; i++ {
+			src, dst := strconv.Itoa(i-1), strconv.Itoa(i)
+			_ = dag.AddEdge(src, dst)
+			_, _ = dag.GetDescendants("0")
+			_, _ = dag.GetAncestors(dst)
+			checkLRU("AddEdge(" + src + ", " + dst + ")")
+		}
+		for i := 1; i < n; i += 2 {
+			src, dst := strconv.Itoa(i-1), strconv.Itoa(i)
+			_ = dag.DeleteEdge(src, dst)
+			_, _ = dag.GetDescendants(src)
+			_, _ = dag.GetAncestors(dst)
+			checkLRU("DeleteEdge(" + src + ", " + dst + ")")
+		}
+		_ = dag.ReplaceVertexValue("0", -1-round)
+		_, _ = dag.GetDescendants("0")
+		checkLRU("ReplaceVertexValue(0)")
+		_ = dag.DeleteVertex(strconv.Itoa(n - 1 - round))
+		checkLRU("DeleteVertex")
+		_ = dag.AddVertexByID(strconv.Itoa(n-1-round), n-1-round)
+	}
+
+}
+
+func TestDisableCachingOption(t *testing.T) {
+	dag := NewDAG()
+	dag.Options(Options{DisableCaching: true})
+	for i := 0; i < 5; i++ {
+		_ = dag.AddVertexByID(strconv.Itoa(i), i)
+	}
+
+	// seed the caches with entries that would be invalidated by the mutations
+	// below, if the caches were maintained
+	sentinel := map[interface{}]struct{}{"sentinel": {}}
+	for i := 0; i < 5; i++ {
+		vHash := dag.hashVertex(i)
+		dag.ancestorsCache[vHash] = sentinel
+		dag.descendantsCache[vHash] = sentinel
+	}
+
+	_ = dag.AddEdges([][2]string{{"0", "1"}, {"1", "2"}, {"2", "3"}, {"0", "3"}, {"3", "4"}})
+	errLoop := dag.AddEdge("4", "0")
+	if _, ok := errLoop.(EdgeLoopError); !ok {
+		t.Errorf("AddEdge(4, 0) expected EdgeLoopError, got %T", errLoop)
+	}
+	_ = dag.DeleteEdge("3", "4")
+	_ = dag.DeleteVertex("4")
+	if len(dag.ancestorsCache) != 5 || len(dag.descendantsCache) != 5 {
+		t.Errorf("mutations touched the caches: %d and %d entries, want 5 and 5", len(dag.ancestorsCache), len(dag.descendantsCache))
+	}
+	for vHash, relatives := range dag.descendantsCache {
+		if _, exists := relatives["sentinel"]; !exists {
+			t.Errorf("descendantsCache[%v] = %v, want the sentinel", vHash, relatives)
+		}
+	}
+
+	// queries are computed from scratch (i.e. neither use nor populate the caches)
+	dag.FlushCaches()
+	descendants, _ := dag.GetDescendants("0")
+	if len(descendants) != 3 {
+		t.Errorf("len(GetDescendants(0)) = %d, want 3", len(descendants))
+	}
+	ancestors, _ := dag.GetAncestors("3")
+	if len(ancestors) != 3 {
+		t.Errorf("len(GetAncestors(3)) = %d, want 3", len(ancestors))
+	}
+	removed := dag.ReduceTransitivelyRemoved()
+	if want := [][2]string{{"0", "3"}}; deep.Equal(removed, want) != nil {
+		t.Errorf("ReduceTransitivelyRemoved() = %v, want %v", removed, want)
+	}
+	if len(dag.ancestorsCache) != 0 || len(dag.descendantsCache) != 0 {
+		t.Errorf("queries populated the caches: %d and %d entries, want 0 and 0", len(dag.ancestorsCache), len(dag.descendantsCache))
+	}
+}
@@ -0,0 +1,54 @@
+package dag
+
+import "sort"
+
+// WeaklyConnectedComponents returns the weakly connected components of the
+// graph. That is, the sets of vertices that are connected when ignoring the
+// direction of the edges. Each component is returned as sorted slice of ids
+// and the components are sorted by their first id.
+func (d *DAG) WeaklyConnectedComponents() [][]string {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.weaklyConnectedComponents()
+}
+
+// IsConnected returns true, if the graph consists of exactly one weakly
+// connected component (see WeaklyConnectedComponents). Thus, IsConnected
+// returns false for an empty graph.
+func (d *DAG) IsConnected() bool {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return len(d.weaklyConnectedComponents()) == 1
+}
+
+func (d *DAG) weaklyConnectedComponents() [][]string {
+	components := make([][]string, 0)
+	visited := make(map[interface{}]struct{}, len(d.vertices))
+	for _, id := range vertexIDs(d.vertexIds) {
+		vHash := d.hashVertex(d.vertexIds[id])
+		if _, exists := visited[vHash]; exists {
+			continue
+		}
+
+		// BFS over the in- and outbound edges
+		visited[vHash] = struct{}{}
+		component := make([]string, 0)
+		queue := []interface{}{vHash}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			component = append(component, d.vertices[current])
+			for _, edges := range []map[interface{}]map[interface{}]struct{}{d.inboundEdge, d.outboundEdge} {
+				for relative := range edges[current] {
+					if _, exists := visited[relative]; !exists {
+						visited[relative] = struct{}{}
+						queue = append(queue, relative)
+					}
+				}
+			}
+		}
+		sort.Strings(component)
+		components = append(components, component)
+	}
+	return components
+}
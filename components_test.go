@@ -0,0 +1,66 @@
+package dag
+
+import (
+	"github.com/go-test/deep"
+	"testing"
+)
+
+func TestDAG_WeaklyConnectedComponents(t *testing.T) {
+	dag := NewDAG()
+	if components := dag.WeaklyConnectedComponents(); len(components) != 0 {
+		t.Errorf("WeaklyConnectedComponents() = %v, want []", components)
+	}
+
+	// two disjoint triangles and an isolated vertex
+	for _, id := range []string{"1", "2", "3", "4", "5", "6", "7"} {
+		_ = dag.AddVertexByID(id, "v"+id)
+	}
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("1", "3")
+	_ = dag.AddEdge("2", "3")
+	_ = dag.AddEdge("6", "4")
+	_ = dag.AddEdge("5", "4")
+	_ = dag.AddEdge("6", "5")
+
+	expected := [][]string{{"1", "2", "3"}, {"4", "5", "6"}, {"7"}}
+	components := dag.WeaklyConnectedComponents()
+	if diff := deep.Equal(components, expected); diff != nil {
+		t.Errorf("WeaklyConnectedComponents() = %v, want %v", components, expected)
+	}
+
+	// connecting the triangles via a common child
+	_ = dag.AddEdge("3", "7")
+	_ = dag.AddEdge("4", "7")
+	expected = [][]string{{"1", "2", "3", "4", "5", "6", "7"}}
+	components = dag.WeaklyConnectedComponents()
+	if diff := deep.Equal(components, expected); diff != nil {
+		t.Errorf("WeaklyConnectedComponents() = %v, want %v", components, expected)
+	}
+}
+
+func TestDAG_IsConnected(t *testing.T) {
+	dag := NewDAG()
+	if dag.IsConnected() {
+		t.Errorf("IsConnected() = true, want false")
+	}
+
+	// single vertex
+	_ = dag.AddVertexByID("1", "v1")
+	if !dag.IsConnected() {
+		t.Errorf("IsConnected() = false, want true")
+	}
+
+	// disconnected
+	_ = dag.AddVertexByID("2", "v2")
+	_ = dag.AddVertexByID("3", "v3")
+	_ = dag.AddEdge("1", "2")
+	if dag.IsConnected() {
+		t.Errorf("IsConnected() = true, want false")
+	}
+
+	// connected (only when ignoring the direction of the edges)
+	_ = dag.AddEdge("3", "2")
+	if !dag.IsConnected() {
+		t.Errorf("IsConnected() = false, want true")
+	}
+}
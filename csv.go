@@ -0,0 +1,75 @@
+package dag
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// edgeListHeader is the header row of CSV edge lists.
+var edgeListHeader = []string{"src", "dst"}
+
+// WriteEdgeListCSV writes all edges of the graph to w as CSV edge list. That
+// is, a header row "src,dst" followed by a row of srcID and dstID per edge.
+// Edges are sorted by srcID and dstID.
+//
+// Note, vertices without any edges, the values of the vertices, and the
+// attributes of the edges are not part of the edge list.
+func (d *DAG) WriteEdgeListCSV(w io.Writer) error {
+	d.muDAG.RLock()
+	edges := make([][2]string, 0, d.getSize())
+	for e := range d.edgeIDs() {
+		edges = append(edges, e)
+	}
+	d.muDAG.RUnlock()
+	sortEdges(edges)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(edgeListHeader); err != nil {
+		return err
+	}
+	for _, e := range edges {
+		if err := cw.Write(e[:]); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadEdgeListCSV returns a new DAG built from the CSV edge list read from r
+// (see WriteEdgeListCSV). Each vertex has its id as value. A leading header row
+// "src,dst" is optional. ReadEdgeListCSV returns an error, if r is not valid
+// CSV, if a row doesn't have exactly two fields, if a field is empty, or if an
+// edge can't be added (e.g. because it would create a loop).
+func ReadEdgeListCSV(r io.Reader) (*DAG, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	dag := NewDAG()
+	for line := 1; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return dag, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && record[0] == edgeListHeader[0] && record[1] == edgeListHeader[1] {
+			continue
+		}
+		if record[0] == "" || record[1] == "" {
+			return nil, fmt.Errorf("line %d: %w", line, IDEmptyError{})
+		}
+		for _, id := range record {
+			if _, errGet := dag.GetVertex(id); errGet == nil {
+				continue
+			}
+			if errAdd := dag.AddVertexByID(id, id); errAdd != nil {
+				return nil, fmt.Errorf("failed to add vertex '%s': %w", id, errAdd)
+			}
+		}
+		if errEdge := dag.AddEdge(record[0], record[1]); errEdge != nil {
+			return nil, fmt.Errorf("failed to add edge from '%s' to '%s': %w", record[0], record[1], errEdge)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package dag
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEdgeListCSV(t *testing.T) {
+	d := NewDAG()
+	for _, id := range []string{"a", "b", "c,d", "e"} {
+		_ = d.AddVertexByID(id, id)
+	}
+	_ = d.AddEdge("a", "b")
+	_ = d.AddEdge("a", "c,d")
+	_ = d.AddEdge("c,d", "e")
+	_ = d.AddEdge("b", "e")
+
+	var buf bytes.Buffer
+	if err := d.WriteEdgeListCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := "src,dst\na,b\na,\"c,d\"\nb,e\n\"c,d\",e\n"
+	if actual := buf.String(); actual != expected {
+		t.Errorf("WriteEdgeListCSV() = %q, want %q", actual, expected)
+	}
+
+	dag, err := ReadEdgeListCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Equal(dag) {
+		t.Errorf("ReadEdgeListCSV() = %v, want %v", dag.String(), d.String())
+	}
+
+	// without header
+	dag, err = ReadEdgeListCSV(strings.NewReader("1,2\n2,3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order, size := dag.GetOrder(), dag.GetSize(); order != 3 || size != 2 {
+		t.Errorf("ReadEdgeListCSV() has %d vertices and %d edges, want 3 and 2", order, size)
+	}
+
+	// cycle
+	_, err = ReadEdgeListCSV(strings.NewReader("src,dst\n1,2\n2,3\n3,1\n"))
+	var errLoop EdgeLoopError
+	if !errors.As(err, &errLoop) {
+		t.Errorf("ReadEdgeListCSV() expected EdgeLoopError, got %T", err)
+	}
+
+	// empty field
+	_, err = ReadEdgeListCSV(strings.NewReader("src,dst\n1,2\n2,\n"))
+	if !errors.Is(err, ErrIDEmpty) || !strings.HasPrefix(err.Error(), "line 3:") {
+		t.Errorf("ReadEdgeListCSV() = %v, want an IDEmptyError on line 3", err)
+	}
+
+	// wrong number of fields
+	_, err = ReadEdgeListCSV(strings.NewReader("1,2,3\n"))
+	if err == nil {
+		t.Errorf("ReadEdgeListCSV() = nil, want an error")
+	}
+}
@@ -2,7 +2,12 @@
 package dag
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/google/uuid"
@@ -24,11 +29,21 @@ type DAG struct {
 	vertexIds        map[string]interface{}
 	inboundEdge      map[interface{}]map[interface{}]struct{}
 	outboundEdge     map[interface{}]map[interface{}]struct{}
+	edgeWeights      map[interface{}]map[interface{}]float64
+	edgeLabels       map[interface{}]map[interface{}]interface{}
+	metadata         map[interface{}]map[string]interface{}
+	edgeCount        int
 	muCache          sync.RWMutex
 	verticesLocked   *dMutex
 	ancestorsCache   map[interface{}]map[interface{}]struct{}
 	descendantsCache map[interface{}]map[interface{}]struct{}
+	ancestorsLRU     *cacheLRU
+	descendantsLRU   *cacheLRU
 	options          Options
+	frozen           bool
+	muListeners      sync.RWMutex
+	listeners        []func(event ChangeEvent)
+	events           []ChangeEvent
 }
 
 // NewDAG creates / initializes a new DAG.
@@ -38,28 +53,39 @@ func NewDAG() *DAG {
 		vertexIds:        make(map[string]interface{}),
 		inboundEdge:      make(map[interface{}]map[interface{}]struct{}),
 		outboundEdge:     make(map[interface{}]map[interface{}]struct{}),
+		edgeWeights:      make(map[interface{}]map[interface{}]float64),
+		edgeLabels:       make(map[interface{}]map[interface{}]interface{}),
+		metadata:         make(map[interface{}]map[string]interface{}),
 		verticesLocked:   newDMutex(),
 		ancestorsCache:   make(map[interface{}]map[interface{}]struct{}),
 		descendantsCache: make(map[interface{}]map[interface{}]struct{}),
+		ancestorsLRU:     newCacheLRU(),
+		descendantsLRU:   newCacheLRU(),
 		options:          defaultOptions(),
 	}
 }
 
 // AddVertex adds the vertex v to the DAG. AddVertex returns an error, if v is
-// nil, v is already part of the graph, or the id of v is already part of the
-// graph.
+// nil, v is not comparable (see Options), v is already part of the graph, or
+// the id of v is already part of the graph.
 func (d *DAG) AddVertex(v interface{}) (string, error) {
 
 	d.muDAG.Lock()
-	defer d.muDAG.Unlock()
+	id, err := d.addVertex(v)
+	d.unlockAndEmit()
 
-	return d.addVertex(v)
+	return id, err
 }
 
 func (d *DAG) addVertex(v interface{}) (string, error) {
+	if d.frozen {
+		return "", FrozenError{}
+	}
 
 	var id string
-	if i, ok := v.(IDInterface); ok {
+	if d.options.VertexIDFunc != nil {
+		id = d.options.VertexIDFunc(v)
+	} else if i, ok := v.(IDInterface); ok {
 		id = i.ID()
 	} else {
 		id = uuid.New().String()
@@ -69,24 +95,57 @@ func (d *DAG) addVertex(v interface{}) (string, error) {
 	return id, err
 }
 
+// AddVertices adds all the given vertices to the DAG and returns their ids (in
+// the same order). Either all vertices are added or none. AddVertices returns
+// the first error (see AddVertex) that occurs and leaves the graph unchanged in
+// this case.
+func (d *DAG) AddVertices(vs []interface{}) ([]string, error) {
+
+	d.muDAG.Lock()
+	defer d.unlockAndEmit()
+
+	ids := make([]string, 0, len(vs))
+	for _, v := range vs {
+		id, err := d.addVertex(v)
+		if err != nil {
+
+			// roll back all vertices added so far
+			for i := len(ids) - 1; i >= 0; i-- {
+				_ = d.deleteVertex(ids[i])
+			}
+			d.discardEvents()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
 // AddVertexByID adds the vertex v and the specified id to the DAG.
 // AddVertexByID returns an error, if v is nil, v is already part of the graph,
 // or the specified id is already part of the graph.
 func (d *DAG) AddVertexByID(id string, v interface{}) error {
 
 	d.muDAG.Lock()
-	defer d.muDAG.Unlock()
+	err := d.addVertexByID(id, v)
+	d.unlockAndEmit()
 
-	return d.addVertexByID(id, v)
+	return err
 }
 
 func (d *DAG) addVertexByID(id string, v interface{}) error {
+	if d.frozen {
+		return FrozenError{}
+	}
 	vHash := d.hashVertex(v)
 
 	// sanity checking
 	if v == nil {
 		return VertexNilError{}
 	}
+	if !hashable(vHash) {
+		return VertexNotComparableError{v}
+	}
 	if _, exists := d.vertices[vHash]; exists {
 		return VertexDuplicateError{v}
 	}
@@ -98,6 +157,7 @@ func (d *DAG) addVertexByID(id string, v interface{}) error {
 	d.vertices[vHash] = id
 	d.vertexIds[id] = v
 
+	d.record(ChangeEvent{Type: VertexAdded, ID: id})
 	return nil
 }
 
@@ -118,13 +178,206 @@ func (d *DAG) GetVertex(id string) (interface{}, error) {
 	return v, nil
 }
 
+// GetVertexOrDefault returns the vertex with the given id or def, if id is
+// the empty string or unknown.
+func (d *DAG) GetVertexOrDefault(id string, def interface{}) interface{} {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if v, exists := d.vertexIds[id]; exists {
+		return v
+	}
+	return def
+}
+
+// GetVertexID returns the id of the vertex v and true, if v is part of the
+// graph, or the empty string and false otherwise. Vertices are looked up via
+// the configured VertexHashFunc (see Options).
+func (d *DAG) GetVertexID(v interface{}) (string, bool) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if v == nil {
+		return "", false
+	}
+	vHash := d.hashVertex(v)
+	if !hashable(vHash) {
+		return "", false
+	}
+	id, exists := d.vertices[vHash]
+	return id, exists
+}
+
+// ReplaceVertexValue replaces the value of the vertex with the given id by v.
+// All edges of the vertex are preserved. ReplaceVertexValue returns an error,
+// if id is empty or unknown, if v is nil, if v is already part of the graph
+// (as another vertex), or if v implements IDInterface and its id differs from
+// id.
+func (d *DAG) ReplaceVertexValue(id string, v interface{}) error {
+
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+
+	if d.frozen {
+		return FrozenError{}
+	}
+
+	if err := d.saneID(id); err != nil {
+		return err
+	}
+	if v == nil {
+		return VertexNilError{}
+	}
+	if i, ok := v.(IDInterface); ok && i.ID() != id {
+		return IDMismatchError{id, i.ID()}
+	}
+
+	oldHash := d.hashVertex(d.vertexIds[id])
+	newHash := d.hashVertex(v)
+	if !hashable(newHash) {
+		return VertexNotComparableError{v}
+	}
+	if otherID, exists := d.vertices[newHash]; exists && otherID != id {
+		return VertexDuplicateError{v}
+	}
+
+	d.vertexIds[id] = v
+	if newHash != oldHash {
+		d.rehashVertex(oldHash, newHash)
+	}
+	return nil
+}
+
+// rehashVertex replaces oldHash by newHash in all internal structures. As the
+// structure of the graph doesn't change, the caches are updated in place (i.e.
+// not invalidated).
+func (d *DAG) rehashVertex(oldHash, newHash interface{}) {
+
+	d.vertices[newHash] = d.vertices[oldHash]
+	delete(d.vertices, oldHash)
+
+	rehashEdges := func(edges map[interface{}]map[interface{}]struct{}, reverse map[interface{}]map[interface{}]struct{}) {
+		if relatives, exists := edges[oldHash]; exists {
+			for relative := range relatives {
+				delete(reverse[relative], oldHash)
+				reverse[relative][newHash] = struct{}{}
+			}
+			edges[newHash] = relatives
+			delete(edges, oldHash)
+		}
+	}
+	rehashEdges(d.outboundEdge, d.inboundEdge)
+	rehashEdges(d.inboundEdge, d.outboundEdge)
+
+	d.rehashEdgeAttributes(oldHash, newHash)
+	if metadata, exists := d.metadata[oldHash]; exists {
+		d.metadata[newHash] = metadata
+		delete(d.metadata, oldHash)
+	}
+
+	d.muCache.Lock()
+	defer d.muCache.Unlock()
+	d.ancestorsLRU.rename(oldHash, newHash)
+	d.descendantsLRU.rename(oldHash, newHash)
+	for _, cache := range []map[interface{}]map[interface{}]struct{}{d.ancestorsCache, d.descendantsCache} {
+		if relatives, exists := cache[oldHash]; exists {
+			cache[newHash] = relatives
+			delete(cache, oldHash)
+		}
+		for _, relatives := range cache {
+			if _, exists := relatives[oldHash]; exists {
+				delete(relatives, oldHash)
+				relatives[newHash] = struct{}{}
+			}
+		}
+	}
+}
+
 // DeleteVertex deletes the vertex with the given id. DeleteVertex also
 // deletes all attached edges (inbound and outbound). DeleteVertex returns
 // an error, if id is empty or unknown.
 func (d *DAG) DeleteVertex(id string) error {
 
 	d.muDAG.Lock()
-	defer d.muDAG.Unlock()
+	err := d.deleteVertex(id)
+	d.unlockAndEmit()
+
+	return err
+}
+
+// DeleteVertices deletes the vertices with the given ids (and all attached
+// edges). The deletion is transactional. That is, if any id is empty or
+// unknown, DeleteVertices returns an error and deletes nothing. In contrast to
+// calling DeleteVertex for each id, the caches are flushed only once.
+func (d *DAG) DeleteVertices(ids []string) error {
+
+	d.muDAG.Lock()
+	defer d.unlockAndEmit()
+
+	if d.frozen {
+		return FrozenError{}
+	}
+
+	for _, id := range ids {
+		if err := d.saneID(id); err != nil {
+			return err
+		}
+	}
+	for _, id := range ids {
+		if v, exists := d.vertexIds[id]; exists {
+			d.removeVertex(id, d.hashVertex(v))
+		}
+	}
+	d.flushCaches()
+	return nil
+}
+
+// Prune deletes all vertices (and their edges) that are neither one of the
+// vertices with the given ids nor one of their descendants. Prune returns the
+// (sorted) ids of the deleted vertices. Prune returns an error, if any of the
+// ids is empty or unknown. In this case nothing is deleted.
+func (d *DAG) Prune(keepRootIDs []string) (removed []string, err error) {
+
+	d.muDAG.Lock()
+	defer d.unlockAndEmit()
+
+	if d.frozen {
+		return nil, FrozenError{}
+	}
+
+	keep := make(map[interface{}]struct{})
+	for _, id := range keepRootIDs {
+		if err := d.saneID(id); err != nil {
+			return nil, err
+		}
+		vHash := d.hashVertex(d.vertexIds[id])
+		keep[vHash] = struct{}{}
+		for descendant := range d.getDescendants(vHash) {
+			keep[descendant] = struct{}{}
+		}
+	}
+
+	removed = make([]string, 0)
+	for vHash, id := range d.vertices {
+		if _, exists := keep[vHash]; !exists {
+			removed = append(removed, id)
+		}
+	}
+	for _, id := range removed {
+		d.removeVertex(id, d.hashVertex(d.vertexIds[id]))
+	}
+	if len(removed) > 0 {
+		d.flushCaches()
+	}
+	sort.Strings(removed)
+	return removed, nil
+}
+
+func (d *DAG) deleteVertex(id string) error {
+
+	if d.frozen {
+		return FrozenError{}
+	}
 
 	if err := d.saneID(id); err != nil {
 		return err
@@ -133,10 +386,36 @@ func (d *DAG) DeleteVertex(id string) error {
 	v := d.vertexIds[id]
 	vHash := d.hashVertex(v)
 
+	if d.options.DisableCaching {
+		d.removeVertex(id, vHash)
+		return nil
+	}
+
 	// get descendents and ancestors as they are now
 	descendants := copyMap(d.getDescendants(vHash))
 	ancestors := copyMap(d.getAncestors(vHash))
 
+	d.removeVertex(id, vHash)
+
+	// for v and all its descendants delete cached ancestors
+	for descendant := range descendants {
+		d.deleteCache(d.ancestorsCache, d.ancestorsLRU, descendant)
+	}
+	d.deleteCache(d.ancestorsCache, d.ancestorsLRU, vHash)
+
+	// for v and all its ancestors delete cached descendants
+	for ancestor := range ancestors {
+		d.deleteCache(d.descendantsCache, d.descendantsLRU, ancestor)
+	}
+	d.deleteCache(d.descendantsCache, d.descendantsLRU, vHash)
+
+	return nil
+}
+
+// removeVertex deletes the vertex with the given id and hash including all
+// attached edges. removeVertex doesn't touch the caches.
+func (d *DAG) removeVertex(id string, vHash interface{}) {
+
 	// delete v in outbound edges of parents
 	if _, exists := d.inboundEdge[vHash]; exists {
 		for parent := range d.inboundEdge[vHash] {
@@ -152,26 +431,21 @@ func (d *DAG) DeleteVertex(id string) error {
 	}
 
 	// delete in- and outbound of v itself
+	d.edgeCount -= len(d.inboundEdge[vHash]) + len(d.outboundEdge[vHash])
 	delete(d.inboundEdge, vHash)
 	delete(d.outboundEdge, vHash)
 
-	// for v and all its descendants delete cached ancestors
-	for descendant := range descendants {
-		delete(d.ancestorsCache, descendant)
-	}
-	delete(d.ancestorsCache, vHash)
+	// delete attributes of in- and outbound edges of v
+	d.deleteVertexEdgeAttributes(vHash)
 
-	// for v and all its ancestors delete cached descendants
-	for ancestor := range ancestors {
-		delete(d.descendantsCache, ancestor)
-	}
-	delete(d.descendantsCache, vHash)
+	// delete the metadata of v
+	delete(d.metadata, vHash)
 
 	// delete v itself
 	delete(d.vertices, vHash)
 	delete(d.vertexIds, id)
 
-	return nil
+	d.record(ChangeEvent{Type: VertexDeleted, ID: id})
 }
 
 // AddEdge adds an edge between srcID and dstID. AddEdge returns an
@@ -180,7 +454,17 @@ func (d *DAG) DeleteVertex(id string) error {
 func (d *DAG) AddEdge(srcID, dstID string) error {
 
 	d.muDAG.Lock()
-	defer d.muDAG.Unlock()
+	err := d.addEdge(srcID, dstID)
+	d.unlockAndEmit()
+
+	return err
+}
+
+func (d *DAG) addEdge(srcID, dstID string) error {
+
+	if d.frozen {
+		return FrozenError{}
+	}
 
 	if err := d.saneID(srcID); err != nil {
 		return err
@@ -204,13 +488,45 @@ func (d *DAG) AddEdge(srcID, dstID string) error {
 		return EdgeDuplicateError{srcID, dstID}
 	}
 
-	// get descendents and ancestors as they are now
-	descendants := copyMap(d.getDescendants(dstHash))
-	ancestors := copyMap(d.getAncestors(srcHash))
+	// get descendents and ancestors as they are now (unless caching is
+	// disabled, then there is no need to know them all)
+	var descendants, ancestors map[interface{}]struct{}
+	var loop bool
+	if d.options.DisableCaching {
+		loop = d.isReachable(dstHash, srcHash, false)
+	} else {
+		descendants = copyMap(d.getDescendants(dstHash))
+		ancestors = copyMap(d.getAncestors(srcHash))
+		_, loop = descendants[srcHash]
+	}
+	if loop {
+		return d.edgeLoopError(srcID, dstID)
+	}
+
+	d.insertEdge(srcHash, dstHash)
+
+	if d.options.DisableCaching {
+		return nil
+	}
+
+	// for dst and all its descendants delete cached ancestors
+	for descendant := range descendants {
+		d.deleteCache(d.ancestorsCache, d.ancestorsLRU, descendant)
+	}
+	d.deleteCache(d.ancestorsCache, d.ancestorsLRU, dstHash)
 
-	if _, exists := descendants[srcHash]; exists {
-		return EdgeLoopError{srcID, dstID}
+	// for src and all its ancestors delete cached descendants
+	for ancestor := range ancestors {
+		d.deleteCache(d.descendantsCache, d.descendantsLRU, ancestor)
 	}
+	d.deleteCache(d.descendantsCache, d.descendantsLRU, srcHash)
+
+	return nil
+}
+
+// insertEdge adds an edge between srcHash and dstHash without any checks and
+// without touching the caches.
+func (d *DAG) insertEdge(srcHash, dstHash interface{}) {
 
 	// prepare d.outbound[src], iff needed
 	if _, exists := d.outboundEdge[srcHash]; !exists {
@@ -219,6 +535,7 @@ func (d *DAG) AddEdge(srcID, dstID string) error {
 
 	// dst is a child of src
 	d.outboundEdge[srcHash][dstHash] = struct{}{}
+	d.edgeCount++
 
 	// prepare d.inboundEdge[dst], iff needed
 	if _, exists := d.inboundEdge[dstHash]; !exists {
@@ -228,21 +545,102 @@ func (d *DAG) AddEdge(srcID, dstID string) error {
 	// src is a parent of dst
 	d.inboundEdge[dstHash][srcHash] = struct{}{}
 
-	// for dst and all its descendants delete cached ancestors
-	for descendant := range descendants {
-		delete(d.ancestorsCache, descendant)
+	d.record(ChangeEvent{Type: EdgeAdded, SrcID: d.vertices[srcHash], DstID: d.vertices[dstHash]})
+}
+
+// AddEdgeAndVertices adds an edge between the vertices src and dst and returns
+// their ids. Vertices that are not yet part of the graph are added first (like
+// AddVertex would do). AddEdgeAndVertices returns an error, if src or dst is
+// nil, if the edge already exists, or if the new edge would create a loop. In
+// case of an error, no vertex is added.
+func (d *DAG) AddEdgeAndVertices(src, dst interface{}) (srcID, dstID string, err error) {
+
+	d.muDAG.Lock()
+	defer d.unlockAndEmit()
+
+	srcID, srcAdded, err := d.getOrAddVertex(src)
+	if err != nil {
+		return "", "", err
+	}
+	dstID, dstAdded, err := d.getOrAddVertex(dst)
+	if err == nil {
+		err = d.addEdge(srcID, dstID)
+	}
+	if err != nil {
+		if dstAdded {
+			_ = d.deleteVertex(dstID)
+		}
+		if srcAdded {
+			_ = d.deleteVertex(srcID)
+		}
+		d.discardEvents()
+		return "", "", err
 	}
-	delete(d.ancestorsCache, dstHash)
+	return srcID, dstID, nil
+}
 
-	// for src and all its ancestors delete cached descendants
-	for ancestor := range ancestors {
-		delete(d.descendantsCache, ancestor)
+// getOrAddVertex returns the id of v, adding v first, if it is not yet part of
+// the graph.
+func (d *DAG) getOrAddVertex(v interface{}) (id string, added bool, err error) {
+	if v != nil {
+		if vHash := d.hashVertex(v); hashable(vHash) {
+			if id, exists := d.vertices[vHash]; exists {
+				return id, false, nil
+			}
+		}
 	}
-	delete(d.descendantsCache, srcHash)
+	id, err = d.addVertex(v)
+	return id, err == nil, err
+}
 
+// AddEdges adds all the given edges (i.e. pairs of srcID and dstID) to the
+// graph. Either all edges are added or none. AddEdges returns the first error
+// (see AddEdge) that occurs and leaves the graph unchanged in this case. Edges
+// are added in the given order. Thus, an edge that would create a loop together
+// with edges given before it, is rejected.
+func (d *DAG) AddEdges(edges [][2]string) error {
+
+	d.muDAG.Lock()
+	defer d.unlockAndEmit()
+
+	for i, e := range edges {
+		if err := d.addEdge(e[0], e[1]); err != nil {
+
+			// roll back all edges added so far
+			for j := i - 1; j >= 0; j-- {
+				_ = d.deleteEdge(edges[j][0], edges[j][1])
+			}
+			d.discardEvents()
+			return err
+		}
+	}
 	return nil
 }
 
+// WouldCreateCycle returns true, if adding an edge from srcID to dstID would
+// create a loop (i.e. if srcID equals dstID or srcID is a descendant of dstID).
+// WouldCreateCycle doesn't change the graph. WouldCreateCycle returns an error,
+// if srcID or dstID are empty or unknown.
+func (d *DAG) WouldCreateCycle(srcID, dstID string) (bool, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if err := d.saneID(srcID); err != nil {
+		return false, err
+	}
+	if err := d.saneID(dstID); err != nil {
+		return false, err
+	}
+	if srcID == dstID {
+		return true, nil
+	}
+
+	srcHash := d.hashVertex(d.vertexIds[srcID])
+	dstHash := d.hashVertex(d.vertexIds[dstID])
+	_, exists := d.getDescendants(dstHash)[srcHash]
+	return exists, nil
+}
+
 // IsEdge returns true, if there exists an edge between srcID and dstID.
 // IsEdge returns false, if there is no such edge. IsEdge returns an error,
 // if srcID or dstID are empty, unknown, or the same.
@@ -288,7 +686,17 @@ func (d *DAG) isEdge(srcHash, dstHash interface{}) bool {
 func (d *DAG) DeleteEdge(srcID, dstID string) error {
 
 	d.muDAG.Lock()
-	defer d.muDAG.Unlock()
+	err := d.deleteEdge(srcID, dstID)
+	d.unlockAndEmit()
+
+	return err
+}
+
+func (d *DAG) deleteEdge(srcID, dstID string) error {
+
+	if d.frozen {
+		return FrozenError{}
+	}
 
 	if err := d.saneID(srcID); err != nil {
 		return err
@@ -309,29 +717,297 @@ func (d *DAG) DeleteEdge(srcID, dstID string) error {
 		return EdgeUnknownError{srcID, dstID}
 	}
 
+	if d.options.DisableCaching {
+		d.removeEdge(srcHash, dstHash)
+		return nil
+	}
+
 	// get descendents and ancestors as they are now
 	descendants := copyMap(d.getDescendants(srcHash))
 	ancestors := copyMap(d.getAncestors(dstHash))
 
-	// delete outbound and inbound
-	delete(d.outboundEdge[srcHash], dstHash)
-	delete(d.inboundEdge[dstHash], srcHash)
+	d.removeEdge(srcHash, dstHash)
 
 	// for src and all its descendants delete cached ancestors
 	for descendant := range descendants {
-		delete(d.ancestorsCache, descendant)
+		d.deleteCache(d.ancestorsCache, d.ancestorsLRU, descendant)
 	}
-	delete(d.ancestorsCache, srcHash)
+	d.deleteCache(d.ancestorsCache, d.ancestorsLRU, srcHash)
 
 	// for dst and all its ancestors delete cached descendants
 	for ancestor := range ancestors {
-		delete(d.descendantsCache, ancestor)
+		d.deleteCache(d.descendantsCache, d.descendantsLRU, ancestor)
+	}
+	d.deleteCache(d.descendantsCache, d.descendantsLRU, dstHash)
+
+	return nil
+}
+
+// IsEdgeV is like IsEdge but takes the values of the vertices instead of their
+// ids. Values are resolved to ids via the configured VertexHashFunc (see
+// GetVertexID). IsEdgeV returns an error, if src or dst are nil, unknown, or
+// the same.
+func (d *DAG) IsEdgeV(src, dst interface{}) (bool, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	srcID, dstID, err := d.resolveEdgeV(src, dst)
+	if err != nil {
+		return false, err
+	}
+	if srcID == dstID {
+		return false, SrcDstEqualError{srcID, dstID}
+	}
+	return d.isEdge(d.hashVertex(d.vertexIds[srcID]), d.hashVertex(d.vertexIds[dstID])), nil
+}
+
+// AddEdgeV is like AddEdge but takes the values of the vertices instead of
+// their ids (see IsEdgeV). AddEdgeV returns an error, if src or dst are nil or
+// unknown, or under the same conditions as AddEdge.
+func (d *DAG) AddEdgeV(src, dst interface{}) error {
+
+	d.muDAG.Lock()
+	defer d.unlockAndEmit()
+
+	srcID, dstID, err := d.resolveEdgeV(src, dst)
+	if err != nil {
+		return err
+	}
+	return d.addEdge(srcID, dstID)
+}
+
+// DeleteEdgeV is like DeleteEdge but takes the values of the vertices instead
+// of their ids (see IsEdgeV). DeleteEdgeV returns an error, if src or dst are
+// nil or unknown, or under the same conditions as DeleteEdge.
+func (d *DAG) DeleteEdgeV(src, dst interface{}) error {
+
+	d.muDAG.Lock()
+	defer d.unlockAndEmit()
+
+	srcID, dstID, err := d.resolveEdgeV(src, dst)
+	if err != nil {
+		return err
+	}
+	return d.deleteEdge(srcID, dstID)
+}
+
+// resolveEdgeV returns the ids of the vertices src and dst.
+func (d *DAG) resolveEdgeV(src, dst interface{}) (srcID, dstID string, err error) {
+	if srcID, err = d.resolveVertex(src); err != nil {
+		return "", "", err
+	}
+	if dstID, err = d.resolveVertex(dst); err != nil {
+		return "", "", err
+	}
+	return srcID, dstID, nil
+}
+
+// resolveVertex returns the id of the vertex v. resolveVertex returns an
+// error, if v is nil or unknown.
+func (d *DAG) resolveVertex(v interface{}) (string, error) {
+	if v == nil {
+		return "", VertexNilError{}
+	}
+	vHash := d.hashVertex(v)
+	if !hashable(vHash) {
+		return "", VertexNotComparableError{v}
+	}
+	id, exists := d.vertices[vHash]
+	if !exists {
+		return "", VertexUnknownError{v}
+	}
+	return id, nil
+}
+
+// removeEdge deletes the edge between srcHash and dstHash (including its
+// attributes) without touching the caches.
+func (d *DAG) removeEdge(srcHash, dstHash interface{}) {
+	delete(d.outboundEdge[srcHash], dstHash)
+	delete(d.inboundEdge[dstHash], srcHash)
+	d.edgeCount--
+	d.deleteEdgeAttributes(srcHash, dstHash)
+
+	d.record(ChangeEvent{Type: EdgeDeleted, SrcID: d.vertices[srcHash], DstID: d.vertices[dstHash]})
+}
+
+// ReplaceEdge atomically replaces the edge between srcID and oldDstID by an
+// edge between srcID and newDstID. Weight and label of the old edge are not
+// carried over. ReplaceEdge returns an error, if any of the ids is empty or
+// unknown, if srcID equals oldDstID or newDstID, if there is no edge between
+// srcID and oldDstID, if the edge between srcID and newDstID already exists,
+// or if it would create a loop. In case of an error, the graph is left
+// unchanged.
+func (d *DAG) ReplaceEdge(srcID, oldDstID, newDstID string) error {
+
+	d.muDAG.Lock()
+	err := d.replaceEdge(srcID, oldDstID, newDstID)
+	d.unlockAndEmit()
+
+	return err
+}
+
+func (d *DAG) replaceEdge(srcID, oldDstID, newDstID string) error {
+
+	if d.frozen {
+		return FrozenError{}
+	}
+
+	for _, id := range []string{srcID, oldDstID, newDstID} {
+		if err := d.saneID(id); err != nil {
+			return err
+		}
+	}
+	for _, dstID := range []string{oldDstID, newDstID} {
+		if srcID == dstID {
+			return SrcDstEqualError{srcID, dstID}
+		}
 	}
-	delete(d.descendantsCache, dstHash)
 
+	srcHash := d.hashVertex(d.vertexIds[srcID])
+	oldDstHash := d.hashVertex(d.vertexIds[oldDstID])
+	newDstHash := d.hashVertex(d.vertexIds[newDstID])
+
+	if !d.isEdge(srcHash, oldDstHash) {
+		return EdgeUnknownError{srcID, oldDstID}
+	}
+	if d.isEdge(srcHash, newDstHash) {
+		return EdgeDuplicateError{srcID, newDstID}
+	}
+
+	// src can't be a descendant of newDst via the old edge (that would be a
+	// loop already), so checking the current graph is sufficient
+	if _, exists := d.getDescendants(newDstHash)[srcHash]; exists {
+		return d.edgeLoopError(srcID, newDstID)
+	}
+
+	// swap the edges
+	d.removeEdge(srcHash, oldDstHash)
+	d.insertEdge(srcHash, newDstHash)
+
+	d.flushCaches()
+	return nil
+}
+
+// ReParent moves the vertex with the id childID under the vertex with the id
+// newParentID. That is, ReParent removes ALL existing inbound edges of child
+// (i.e. child loses all its parents) and adds an edge from newParent to child.
+// If newParent already is a parent of child, this edge (including its weight
+// and label) is kept. Weights and labels of the removed edges are lost.
+// ReParent returns an error, if childID or newParentID are empty, unknown, or
+// the same, or if the new edge would create a loop (i.e. newParent is a
+// descendant of child). In case of an error, the graph is left unchanged.
+func (d *DAG) ReParent(childID, newParentID string) error {
+
+	d.muDAG.Lock()
+	err := d.reParent(childID, newParentID)
+	d.unlockAndEmit()
+
+	return err
+}
+
+func (d *DAG) reParent(childID, newParentID string) error {
+
+	if d.frozen {
+		return FrozenError{}
+	}
+
+	if err := d.saneID(childID); err != nil {
+		return err
+	}
+	if err := d.saneID(newParentID); err != nil {
+		return err
+	}
+	if childID == newParentID {
+		return SrcDstEqualError{newParentID, childID}
+	}
+
+	// removing the inbound edges of child doesn't change its descendants, so
+	// checking the current graph is sufficient
+	childHash := d.hashVertex(d.vertexIds[childID])
+	newParentHash := d.hashVertex(d.vertexIds[newParentID])
+	if d.isReachable(childHash, newParentHash, false) {
+		return d.edgeLoopError(newParentID, childID)
+	}
+
+	parents, _ := d.getParents(childID)
+	for _, parentID := range vertexIDs(parents) {
+		if parentID == newParentID {
+			continue
+		}
+		if err := d.deleteEdge(parentID, childID); err != nil {
+			return err
+		}
+	}
+	if _, exists := parents[newParentID]; !exists {
+		return d.addEdge(newParentID, childID)
+	}
+	return nil
+}
+
+// ContractEdge contracts the edge between srcID and dstID. That is, the vertex
+// with the id dstID is merged into the vertex with the id srcID: src keeps its
+// value and inherits the children and the (other) parents of dst, and dst is
+// deleted afterwards. Edges that already exist (e.g. src was already a parent
+// of a child of dst) are not duplicated and edges from parents of dst that
+// would create a loop (i.e. parents of dst that are also descendants of src)
+// are skipped. Weights and labels of rewired edges are not carried over.
+// ContractEdge returns an error, if srcID or dstID are empty, unknown, or the
+// same, or if there is no edge between srcID and dstID. In case of an error,
+// the graph is left unchanged.
+func (d *DAG) ContractEdge(srcID, dstID string) error {
+
+	d.muDAG.Lock()
+	defer d.unlockAndEmit()
+
+	if d.frozen {
+		return FrozenError{}
+	}
+
+	srcHash, dstHash, err := d.saneEdge(srcID, dstID)
+	if err != nil {
+		return err
+	}
+
+	// determine all rewired edges before changing anything, such that the
+	// contraction can't fail halfway (a parent of dst that is a descendant of
+	// src would create a loop)
+	descendants := d.getDescendants(srcHash)
+	var children, parents []interface{}
+	for _, child := range d.sortedHashes(d.outboundEdge[dstHash]) {
+		if !d.isEdge(srcHash, child) {
+			children = append(children, child)
+		}
+	}
+	for _, parent := range d.sortedHashes(d.inboundEdge[dstHash]) {
+		if _, loop := descendants[parent]; parent != srcHash && !loop && !d.isEdge(parent, srcHash) {
+			parents = append(parents, parent)
+		}
+	}
+
+	d.removeVertex(dstID, dstHash)
+	for _, child := range children {
+		d.insertEdge(srcHash, child)
+	}
+	for _, parent := range parents {
+		d.insertEdge(parent, srcHash)
+	}
+	d.flushCaches()
 	return nil
 }
 
+// sortedHashes returns the given hashes ordered by the ids of the respective
+// vertices.
+func (d *DAG) sortedHashes(hashes map[interface{}]struct{}) []interface{} {
+	sorted := make([]interface{}, 0, len(hashes))
+	for h := range hashes {
+		sorted = append(sorted, h)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return d.vertices[sorted[i]] < d.vertices[sorted[j]]
+	})
+	return sorted
+}
+
 // GetOrder returns the number of vertices in the graph.
 func (d *DAG) GetOrder() int {
 	d.muDAG.RLock()
@@ -351,11 +1027,27 @@ func (d *DAG) GetSize() int {
 }
 
 func (d *DAG) getSize() int {
-	count := 0
-	for _, value := range d.outboundEdge {
-		count += len(value)
+	return d.edgeCount
+}
+
+// Stats returns the number of vertices (i.e. the order), the number of edges
+// (i.e. the size), the number of roots, and the number of leaves of the graph.
+// In contrast to calling GetOrder, GetSize, etc. one after another, all numbers
+// are computed under a single lock. That is, they are consistent with each
+// other even while the graph is concurrently modified.
+func (d *DAG) Stats() (order, size, roots, leaves int) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	for vHash := range d.vertices {
+		if len(d.inboundEdge[vHash]) == 0 {
+			roots++
+		}
+		if len(d.outboundEdge[vHash]) == 0 {
+			leaves++
+		}
 	}
-	return count
+	return d.getOrder(), d.getSize(), roots, leaves
 }
 
 // GetLeaves returns all vertices without children.
@@ -377,6 +1069,22 @@ func (d *DAG) getLeaves() map[string]interface{} {
 	return leaves
 }
 
+// GetLeavesMap returns the ids and values of all vertices without children.
+// In contrast to GetLeaves, the values of the returned map are always the
+// values of the vertices (and not their hashes, see Options.VertexHashFunc).
+func (d *DAG) GetLeavesMap() map[string]interface{} {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.valuesOf(d.getLeaves())
+}
+
+// SortedLeafIDs returns the ids of all leaves in ascending order.
+func (d *DAG) SortedLeafIDs() []string {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return vertexIDs(d.getLeaves())
+}
+
 // IsLeaf returns true, if the vertex with the given id has no children. IsLeaf
 // returns an error, if id is empty or unknown.
 func (d *DAG) IsLeaf(id string) (bool, error) {
@@ -417,6 +1125,31 @@ func (d *DAG) getRoots() map[string]interface{} {
 	return roots
 }
 
+// GetRootsMap returns the ids and values of all vertices without parents.
+// In contrast to GetRoots, the values of the returned map are always the
+// values of the vertices (and not their hashes, see Options.VertexHashFunc).
+func (d *DAG) GetRootsMap() map[string]interface{} {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.valuesOf(d.getRoots())
+}
+
+// valuesOf returns a map of the ids of the given vertices to their values.
+func (d *DAG) valuesOf(vertices map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(vertices))
+	for id := range vertices {
+		values[id] = d.vertexIds[id]
+	}
+	return values
+}
+
+// SortedRootIDs returns the ids of all roots in ascending order.
+func (d *DAG) SortedRootIDs() []string {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return vertexIDs(d.getRoots())
+}
+
 // IsRoot returns true, if the vertex with the given id has no parents. IsRoot
 // returns an error, if id is empty or unknown.
 func (d *DAG) IsRoot(id string) (bool, error) {
@@ -438,6 +1171,51 @@ func (d *DAG) isRoot(id string) bool {
 	return false
 }
 
+// GetLeavesOf returns all descendants of the vertex with the given id that
+// have no children. GetLeavesOf returns an empty map, if the vertex itself is
+// a leaf. GetLeavesOf returns an error, if id is empty or unknown.
+func (d *DAG) GetLeavesOf(id string) (map[string]interface{}, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.getRelativesWithout(id, false)
+}
+
+// GetRootsOf returns all ancestors of the vertex with the given id that have
+// no parents. GetRootsOf returns an empty map, if the vertex itself is a root.
+// GetRootsOf returns an error, if id is empty or unknown.
+func (d *DAG) GetRootsOf(id string) (map[string]interface{}, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.getRelativesWithout(id, true)
+}
+
+// getRelativesWithout returns the ancestors (asc) or descendants (!asc) of the
+// vertex with the given id that have no parents (asc) or no children (!asc).
+func (d *DAG) getRelativesWithout(id string, asc bool) (map[string]interface{}, error) {
+	if err := d.saneID(id); err != nil {
+		return nil, err
+	}
+	vHash := d.hashVertex(d.vertexIds[id])
+
+	var relatives map[interface{}]struct{}
+	var edges map[interface{}]map[interface{}]struct{}
+	if asc {
+		relatives = d.getAncestors(vHash)
+		edges = d.inboundEdge
+	} else {
+		relatives = d.getDescendants(vHash)
+		edges = d.outboundEdge
+	}
+
+	result := make(map[string]interface{})
+	for relative := range relatives {
+		if len(edges[relative]) == 0 {
+			result[d.vertices[relative]] = relative
+		}
+	}
+	return result, nil
+}
+
 // GetVertices returns all vertices.
 func (d *DAG) GetVertices() map[string]interface{} {
 	d.muDAG.RLock()
@@ -449,11 +1227,22 @@ func (d *DAG) GetVertices() map[string]interface{} {
 	return out
 }
 
+// SortedVertexIDs returns the ids of all vertices in ascending order.
+func (d *DAG) SortedVertexIDs() []string {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return vertexIDs(d.vertexIds)
+}
+
 // GetParents returns the all parents of the vertex with the id
 // id. GetParents returns an error, if id is empty or unknown.
 func (d *DAG) GetParents(id string) (map[string]interface{}, error) {
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
+	return d.getParents(id)
+}
+
+func (d *DAG) getParents(id string) (map[string]interface{}, error) {
 	if err := d.saneID(id); err != nil {
 		return nil, err
 	}
@@ -467,26 +1256,122 @@ func (d *DAG) GetParents(id string) (map[string]interface{}, error) {
 	return parents, nil
 }
 
-// GetChildren returns all children of the vertex with the id
-// id. GetChildren returns an error, if id is empty or unknown.
-func (d *DAG) GetChildren(id string) (map[string]interface{}, error) {
+// GetChildren returns all children of the vertex with the id
+// id. GetChildren returns an error, if id is empty or unknown.
+func (d *DAG) GetChildren(id string) (map[string]interface{}, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.getChildren(id)
+}
+
+func (d *DAG) getChildren(id string) (map[string]interface{}, error) {
+	if err := d.saneID(id); err != nil {
+		return nil, err
+	}
+	v := d.vertexIds[id]
+	vHash := d.hashVertex(v)
+	children := make(map[string]interface{})
+	for cv := range d.outboundEdge[vHash] {
+		cid := d.vertices[cv]
+		children[cid] = cv
+	}
+	return children, nil
+}
+
+// GetIncidentEdges returns all edges of the vertex with the id id as pairs of
+// srcID and dstID, separated into inbound edges (i.e. from parents) and
+// outbound edges (i.e. to children). Both are sorted. GetIncidentEdges returns
+// an error, if id is empty or unknown.
+func (d *DAG) GetIncidentEdges(id string) (inbound [][2]string, outbound [][2]string, err error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err = d.saneID(id); err != nil {
+		return nil, nil, err
+	}
+	vHash := d.hashVertex(d.vertexIds[id])
+	inbound = make([][2]string, 0, len(d.inboundEdge[vHash]))
+	for pv := range d.inboundEdge[vHash] {
+		inbound = append(inbound, [2]string{d.vertices[pv], id})
+	}
+	outbound = make([][2]string, 0, len(d.outboundEdge[vHash]))
+	for cv := range d.outboundEdge[vHash] {
+		outbound = append(outbound, [2]string{id, d.vertices[cv]})
+	}
+	sortEdges(inbound)
+	sortEdges(outbound)
+	return inbound, outbound, nil
+}
+
+// GetParentCount returns the number of parents of the vertex with the id
+// id. GetParentCount returns an error, if id is empty or unknown.
+func (d *DAG) GetParentCount(id string) (int, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(id); err != nil {
+		return 0, err
+	}
+	v := d.vertexIds[id]
+	return len(d.inboundEdge[d.hashVertex(v)]), nil
+}
+
+// GetChildCount returns the number of children of the vertex with the id
+// id. GetChildCount returns an error, if id is empty or unknown.
+func (d *DAG) GetChildCount(id string) (int, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(id); err != nil {
+		return 0, err
+	}
+	v := d.vertexIds[id]
+	return len(d.outboundEdge[d.hashVertex(v)]), nil
+}
+
+// InDegrees returns the number of parents (i.e. the in-degree) of each vertex
+// mapped by the ids of the vertices.
+func (d *DAG) InDegrees() map[string]int {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.degrees(d.inboundEdge)
+}
+
+// OutDegrees returns the number of children (i.e. the out-degree) of each
+// vertex mapped by the ids of the vertices.
+func (d *DAG) OutDegrees() map[string]int {
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
-	return d.getChildren(id)
+	return d.degrees(d.outboundEdge)
 }
 
-func (d *DAG) getChildren(id string) (map[string]interface{}, error) {
-	if err := d.saneID(id); err != nil {
-		return nil, err
+func (d *DAG) degrees(edges map[interface{}]map[interface{}]struct{}) map[string]int {
+	degrees := make(map[string]int, len(d.vertices))
+	for vHash, id := range d.vertices {
+		degrees[id] = len(edges[vHash])
 	}
-	v := d.vertexIds[id]
-	vHash := d.hashVertex(v)
-	children := make(map[string]interface{})
-	for cv := range d.outboundEdge[vHash] {
-		cid := d.vertices[cv]
-		children[cid] = cv
+	return degrees
+}
+
+// AncestorCount returns the number of ancestors of the vertex with the id id.
+// In contrast to len(GetAncestors(id)), AncestorCount doesn't copy the
+// ancestors. AncestorCount returns an error, if id is empty or unknown.
+//
+// Note, like GetAncestors, AncestorCount populates the ancestors-cache as
+// needed.
+func (d *DAG) AncestorCount(id string) (int, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(id); err != nil {
+		return 0, err
 	}
-	return children, nil
+	return len(d.getAncestors(d.hashVertex(d.vertexIds[id]))), nil
+}
+
+// GetAncestorsUntil is like GetDescendantsUntil but for ancestors. That is,
+// it returns the ancestors of the vertex with the id id without walking past
+// (i.e. to the parents of) vertices for which stop returns true.
+func (d *DAG) GetAncestorsUntil(id string, stop func(id string, v interface{}) bool) (map[string]interface{}, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.getRelativesUntil(id, stop, true)
 }
 
 // GetAncestors return all ancestors of the vertex with the id id. GetAncestors
@@ -498,6 +1383,10 @@ func (d *DAG) getChildren(id string) (map[string]interface{}, error) {
 func (d *DAG) GetAncestors(id string) (map[string]interface{}, error) {
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
+	return d.getAncestorsByID(id)
+}
+
+func (d *DAG) getAncestorsByID(id string) (map[string]interface{}, error) {
 	if err := d.saneID(id); err != nil {
 		return nil, err
 	}
@@ -513,11 +1402,17 @@ func (d *DAG) GetAncestors(id string) (map[string]interface{}, error) {
 
 func (d *DAG) getAncestors(vHash interface{}) map[interface{}]struct{} {
 
+	// without caching, always start from scratch
+	if d.options.DisableCaching {
+		return d.collectRelatives(vHash, d.inboundEdge)
+	}
+
 	// in the best case we have already a populated cache
 	d.muCache.RLock()
 	cache, exists := d.ancestorsCache[vHash]
 	d.muCache.RUnlock()
 	if exists {
+		d.touchCache(d.ancestorsLRU, vHash)
 		return cache
 	}
 
@@ -531,6 +1426,7 @@ func (d *DAG) getAncestors(vHash interface{}) map[interface{}]struct{} {
 	cache, exists = d.ancestorsCache[vHash]
 	d.muCache.RUnlock()
 	if exists {
+		d.touchCache(d.ancestorsLRU, vHash)
 		return cache
 	}
 
@@ -552,9 +1448,7 @@ func (d *DAG) getAncestors(vHash interface{}) map[interface{}]struct{} {
 	}
 
 	// remember the collected descendents
-	d.muCache.Lock()
-	d.ancestorsCache[vHash] = cache
-	d.muCache.Unlock()
+	d.storeCache(d.ancestorsCache, d.ancestorsLRU, vHash, cache)
 	return cache
 }
 
@@ -566,8 +1460,6 @@ func (d *DAG) getAncestors(vHash interface{}) map[interface{}]struct{} {
 // Note, there is no order between sibling vertices. Two consecutive runs of
 // GetOrderedAncestors may return different results.
 func (d *DAG) GetOrderedAncestors(id string) ([]string, error) {
-	d.muDAG.RLock()
-	defer d.muDAG.RUnlock()
 	ids, _, err := d.AncestorsWalker(id)
 	if err != nil {
 		return nil, err
@@ -586,7 +1478,19 @@ func (d *DAG) GetOrderedAncestors(id string) ([]string, error) {
 //
 // Note, there is no order between sibling vertices. Two consecutive runs of
 // AncestorsWalker may return different results.
+//
+// Note, the signal channel is never closed. Thus, it is safe to send a signal
+// at any time (also after the last vertex has been returned), as long as at
+// most one signal is sent.
 func (d *DAG) AncestorsWalker(id string) (chan string, chan bool, error) {
+	return d.AncestorsWalkerMaxDepth(id, 0)
+}
+
+// AncestorsWalkerMaxDepth is like AncestorsWalker but only walks ancestors up
+// to the given depth (i.e. a depth of 1 only walks the parents, a depth of 2
+// the parents and grandparents, and so on). A maxDepth of 0 or less means
+// unlimited.
+func (d *DAG) AncestorsWalkerMaxDepth(id string, maxDepth int) (chan string, chan bool, error) {
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
 	if err := d.saneID(id); err != nil {
@@ -598,20 +1502,73 @@ func (d *DAG) AncestorsWalker(id string) (chan string, chan bool, error) {
 		d.muDAG.RLock()
 		v := d.vertexIds[id]
 		vHash := d.hashVertex(v)
-		d.walkAncestors(vHash, ids, signal)
+		d.walkAncestors(vHash, maxDepth, func(top interface{}) bool {
+			select {
+			case <-signal:
+				return false
+			case ids <- d.vertices[top]:
+				return true
+			}
+		})
 		d.muDAG.RUnlock()
 		close(ids)
-		close(signal)
 	}()
 	return ids, signal, nil
 }
 
-func (d *DAG) walkAncestors(vHash interface{}, ids chan string, signal chan bool) {
+// AncestorsWalkerContext is like AncestorsWalker, but instead of a signal
+// channel the given context is used to stop further walking. That is, the
+// walk stops and the returned channel is closed, as soon as ctx is done.
+func (d *DAG) AncestorsWalkerContext(ctx context.Context, id string) (chan string, error) {
+	return d.walkerContext(ctx, id, true)
+}
+
+// DescendantsWalkerContext is like DescendantsWalker, but instead of a signal
+// channel the given context is used to stop further walking. That is, the
+// walk stops and the returned channel is closed, as soon as ctx is done.
+func (d *DAG) DescendantsWalkerContext(ctx context.Context, id string) (chan string, error) {
+	return d.walkerContext(ctx, id, false)
+}
+
+func (d *DAG) walkerContext(ctx context.Context, id string, asc bool) (chan string, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(id); err != nil {
+		return nil, err
+	}
+	ids := make(chan string)
+	go func() {
+		d.muDAG.RLock()
+		v := d.vertexIds[id]
+		vHash := d.hashVertex(v)
+		send := func(top interface{}) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case ids <- d.vertices[top]:
+				return true
+			}
+		}
+		if asc {
+			d.walkAncestors(vHash, 0, send)
+		} else {
+			d.walkDescendants(vHash, send)
+		}
+		d.muDAG.RUnlock()
+		close(ids)
+	}()
+	return ids, nil
+}
+
+// walkAncestors walks all ancestors of the vertex with the hash vHash (up to
+// maxDepth) in a breath first order and calls send for each of them. The walk
+// stops, if send returns false.
+func (d *DAG) walkAncestors(vHash interface{}, maxDepth int, send func(top interface{}) bool) {
 
 	var fifo []interface{}
-	visited := make(map[interface{}]struct{})
+	depths := make(map[interface{}]int)
 	for parent := range d.inboundEdge[vHash] {
-		visited[parent] = struct{}{}
+		depths[parent] = 1
 		fifo = append(fifo, parent)
 	}
 	for {
@@ -620,21 +1577,82 @@ func (d *DAG) walkAncestors(vHash interface{}, ids chan string, signal chan bool
 		}
 		top := fifo[0]
 		fifo = fifo[1:]
-		for parent := range d.inboundEdge[top] {
-			if _, exists := visited[parent]; !exists {
-				visited[parent] = struct{}{}
-				fifo = append(fifo, parent)
+		if maxDepth <= 0 || depths[top] < maxDepth {
+			for parent := range d.inboundEdge[top] {
+				if _, exists := depths[parent]; !exists {
+					depths[parent] = depths[top] + 1
+					fifo = append(fifo, parent)
+				}
 			}
 		}
-		select {
-		case <-signal:
+		if !send(top) {
 			return
-		default:
-			ids <- d.vertices[top]
 		}
 	}
 }
 
+// DescendantCount returns the number of descendants of the vertex with the id
+// id. In contrast to len(GetDescendants(id)), DescendantCount doesn't copy the
+// descendants. DescendantCount returns an error, if id is empty or unknown.
+//
+// Note, like GetDescendants, DescendantCount populates the descendants-cache
+// as needed.
+func (d *DAG) DescendantCount(id string) (int, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(id); err != nil {
+		return 0, err
+	}
+	return len(d.getDescendants(d.hashVertex(d.vertexIds[id]))), nil
+}
+
+// GetDescendantsUntil returns the ids and values of the descendants of the
+// vertex with the id id, but doesn't walk past vertices for which stop returns
+// true. That is, such a vertex is part of the result, but its children are
+// only, if they are reachable otherwise. stop is not called for the vertex
+// with the id id itself. GetDescendantsUntil returns an error, if id is empty
+// or unknown.
+//
+// Note, in contrast to GetDescendants, GetDescendantsUntil neither uses nor
+// populates the descendants-cache.
+func (d *DAG) GetDescendantsUntil(id string, stop func(id string, v interface{}) bool) (map[string]interface{}, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.getRelativesUntil(id, stop, false)
+}
+
+// getRelativesUntil does a breadth first search starting at the vertex with
+// the id id (depending on the direction either via parents or children) not
+// expanding vertices for which stop returns true.
+func (d *DAG) getRelativesUntil(id string, stop func(id string, v interface{}) bool, asc bool) (map[string]interface{}, error) {
+	if err := d.saneID(id); err != nil {
+		return nil, err
+	}
+	edges := d.outboundEdge
+	if asc {
+		edges = d.inboundEdge
+	}
+
+	relatives := make(map[string]interface{})
+	fifo := []interface{}{d.hashVertex(d.vertexIds[id])}
+	for len(fifo) > 0 {
+		top := fifo[0]
+		fifo = fifo[1:]
+		for relative := range edges[top] {
+			relativeID := d.vertices[relative]
+			if _, exists := relatives[relativeID]; exists {
+				continue
+			}
+			value := d.vertexIds[relativeID]
+			relatives[relativeID] = value
+			if !stop(relativeID, value) {
+				fifo = append(fifo, relative)
+			}
+		}
+	}
+	return relatives, nil
+}
+
 // GetDescendants return all descendants of the vertex with id id.
 // GetDescendants returns an error, if id is empty or unknown.
 //
@@ -645,7 +1663,10 @@ func (d *DAG) walkAncestors(vHash interface{}, ids chan string, signal chan bool
 func (d *DAG) GetDescendants(id string) (map[string]interface{}, error) {
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
+	return d.getDescendantsByID(id)
+}
 
+func (d *DAG) getDescendantsByID(id string) (map[string]interface{}, error) {
 	if err := d.saneID(id); err != nil {
 		return nil, err
 	}
@@ -662,11 +1683,17 @@ func (d *DAG) GetDescendants(id string) (map[string]interface{}, error) {
 
 func (d *DAG) getDescendants(vHash interface{}) map[interface{}]struct{} {
 
+	// without caching, always start from scratch
+	if d.options.DisableCaching {
+		return d.collectRelatives(vHash, d.outboundEdge)
+	}
+
 	// in the best case we have already a populated cache
 	d.muCache.RLock()
 	cache, exists := d.descendantsCache[vHash]
 	d.muCache.RUnlock()
 	if exists {
+		d.touchCache(d.descendantsLRU, vHash)
 		return cache
 	}
 
@@ -680,6 +1707,7 @@ func (d *DAG) getDescendants(vHash interface{}) map[interface{}]struct{} {
 	cache, exists = d.descendantsCache[vHash]
 	d.muCache.RUnlock()
 	if exists {
+		d.touchCache(d.descendantsLRU, vHash)
 		return cache
 	}
 
@@ -708,12 +1736,105 @@ func (d *DAG) getDescendants(vHash interface{}) map[interface{}]struct{} {
 	}
 
 	// remember the collected descendents
-	d.muCache.Lock()
-	d.descendantsCache[vHash] = cache
-	d.muCache.Unlock()
+	d.storeCache(d.descendantsCache, d.descendantsLRU, vHash, cache)
 	return cache
 }
 
+// IsDescendant returns true, if the vertex with the id descendantID is a
+// descendant of the vertex with the id ancestorID. IsDescendant returns an
+// error, if ancestorID or descendantID are empty, unknown, or the same.
+//
+// Note, unlike GetDescendants, IsDescendant neither uses nor populates the
+// descendants-cache but stops searching as soon as the vertex is found.
+func (d *DAG) IsDescendant(ancestorID, descendantID string) (bool, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.isRelative(ancestorID, descendantID, false)
+}
+
+// IsAncestor returns true, if the vertex with the id ancestorID is an
+// ancestor of the vertex with the id id (i.e. IsAncestor(a, b) equals
+// IsDescendant(b, a)). IsAncestor returns an error, if id or ancestorID are
+// empty, unknown, or the same.
+//
+// Note, unlike GetAncestors, IsAncestor neither uses nor populates the
+// ancestors-cache but stops searching as soon as the vertex is found.
+func (d *DAG) IsAncestor(id, ancestorID string) (bool, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.isRelative(id, ancestorID, true)
+}
+
+// FilterDescendants returns for each of the vertices with the ids
+// candidateIDs, whether it is a descendant of the vertex with the id
+// sourceID. A vertex is not a descendant of itself. FilterDescendants returns
+// an error, if sourceID or any of the candidateIDs is empty or unknown.
+//
+// Note, in contrast to calling IsDescendant for each candidate, the
+// descendants of sourceID are collected only once (using the
+// descendants-cache, see GetDescendants).
+func (d *DAG) FilterDescendants(sourceID string, candidateIDs []string) (map[string]bool, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if err := d.saneID(sourceID); err != nil {
+		return nil, err
+	}
+	for _, id := range candidateIDs {
+		if err := d.saneID(id); err != nil {
+			return nil, err
+		}
+	}
+
+	descendants := d.getDescendants(d.hashVertex(d.vertexIds[sourceID]))
+	result := make(map[string]bool, len(candidateIDs))
+	for _, id := range candidateIDs {
+		_, result[id] = descendants[d.hashVertex(d.vertexIds[id])]
+	}
+	return result, nil
+}
+
+func (d *DAG) isRelative(id, relativeID string, asc bool) (bool, error) {
+	if err := d.saneID(id); err != nil {
+		return false, err
+	}
+	if err := d.saneID(relativeID); err != nil {
+		return false, err
+	}
+	if id == relativeID {
+		return false, SrcDstEqualError{id, relativeID}
+	}
+	vHash := d.hashVertex(d.vertexIds[id])
+	relativeHash := d.hashVertex(d.vertexIds[relativeID])
+	return d.isReachable(vHash, relativeHash, asc), nil
+}
+
+// isReachable does a breadth first search starting at vHash (depending on the
+// direction either via parents or children) and returns true as soon as
+// targetHash is found.
+func (d *DAG) isReachable(vHash, targetHash interface{}, asc bool) bool {
+	edges := d.outboundEdge
+	if asc {
+		edges = d.inboundEdge
+	}
+	fifo := []interface{}{vHash}
+	visited := map[interface{}]struct{}{vHash: {}}
+	for len(fifo) > 0 {
+		top := fifo[0]
+		fifo = fifo[1:]
+		for relative := range edges[top] {
+			if relative == targetHash {
+				return true
+			}
+			if _, exists := visited[relative]; !exists {
+				visited[relative] = struct{}{}
+				fifo = append(fifo, relative)
+			}
+		}
+	}
+	return false
+}
+
 // GetOrderedDescendants returns all descendants of the vertex with id id
 // in a breath-first order. Only the first occurrence of each vertex is
 // returned. GetOrderedDescendants returns an error, if id is empty or
@@ -722,8 +1843,6 @@ func (d *DAG) getDescendants(vHash interface{}) map[interface{}]struct{} {
 // Note, there is no order between sibling vertices. Two consecutive runs of
 // GetOrderedDescendants may return different results.
 func (d *DAG) GetOrderedDescendants(id string) ([]string, error) {
-	d.muDAG.RLock()
-	defer d.muDAG.RUnlock()
 	ids, _, err := d.DescendantsWalker(id)
 	if err != nil {
 		return nil, err
@@ -735,13 +1854,54 @@ func (d *DAG) GetOrderedDescendants(id string) ([]string, error) {
 	return descendants, nil
 }
 
+// VertexDepth is the id of a vertex together with its distance to another
+// vertex (see GetOrderedDescendantsWithDepth).
+type VertexDepth struct {
+	ID    string
+	Depth int
+}
+
+// GetOrderedDescendantsWithDepth returns all descendants of the vertex with id
+// id in a breath-first order together with their depth relative to the vertex
+// (i.e. the length of the shortest path from the vertex). Only the first
+// occurrence of each vertex is returned and siblings are ordered by their ids.
+// GetOrderedDescendantsWithDepth returns an error, if id is empty or unknown.
+func (d *DAG) GetOrderedDescendantsWithDepth(id string) ([]VertexDepth, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if err := d.saneID(id); err != nil {
+		return nil, err
+	}
+
+	vHash := d.hashVertex(d.vertexIds[id])
+	descendants := make([]VertexDepth, 0)
+	fifo := []interface{}{vHash}
+	depths := map[interface{}]int{vHash: 0}
+	for len(fifo) > 0 {
+		top := fifo[0]
+		fifo = fifo[1:]
+		children, _ := d.getChildren(d.vertices[top])
+		for _, childID := range vertexIDs(children) {
+			child := d.hashVertex(d.vertexIds[childID])
+			if _, exists := depths[child]; !exists {
+				depths[child] = depths[top] + 1
+				descendants = append(descendants, VertexDepth{ID: childID, Depth: depths[child]})
+				fifo = append(fifo, child)
+			}
+		}
+	}
+	return descendants, nil
+}
+
 // GetDescendantsGraph returns a new DAG consisting of the vertex with id id and
 // all its descendants (i.e. the subgraph). GetDescendantsGraph also returns the
 // id of the (copy of the) given vertex within the new graph (i.e. the id of the
 // single root of the new graph). GetDescendantsGraph returns an error, if id is
 // empty or unknown.
 //
-// Note, the new graph is a copy of the relevant part of the original graph.
+// Note, the new graph is a copy of the relevant part of the original graph
+// (including the weights and labels of the edges).
 func (d *DAG) GetDescendantsGraph(id string) (*DAG, string, error) {
 
 	// recursively add the current vertex and all its descendants
@@ -754,34 +1914,122 @@ func (d *DAG) GetDescendantsGraph(id string) (*DAG, string, error) {
 // single leaf of the new graph). GetAncestorsGraph returns an error, if id is
 // empty or unknown.
 //
-// Note, the new graph is a copy of the relevant part of the original graph.
+// Note, the new graph is a copy of the relevant part of the original graph
+// (including the weights and labels of the edges).
 func (d *DAG) GetAncestorsGraph(id string) (*DAG, string, error) {
 
 	// recursively add the current vertex and all its ancestors
 	return d.getRelativesGraph(id, true)
 }
 
-func (d *DAG) getRelativesGraph(id string, asc bool) (*DAG, string, error) {
-	// sanity checking
-	if id == "" {
-		return nil, "", IDEmptyError{}
+// GetDescendantsGraphMaxDepth is like GetDescendantsGraph, but the new DAG
+// only contains the descendants that are at most maxDepth edges away from the
+// vertex with id id (and all edges between these vertices). Like for
+// GetDescendantsGraph, the new graph is a copy and the returned id is the id of
+// the (copy of the) given vertex within the new graph. A maxDepth of 0 or less
+// returns a graph consisting of the given vertex only.
+// GetDescendantsGraphMaxDepth returns an error, if id is empty or unknown.
+func (d *DAG) GetDescendantsGraphMaxDepth(id string, maxDepth int) (*DAG, string, error) {
+	return d.getRelativesGraphMaxDepth(id, maxDepth, false)
+}
+
+// GetAncestorsGraphMaxDepth is like GetDescendantsGraphMaxDepth but for
+// ancestors (i.e. it returns the ancestors that are at most maxDepth edges
+// away from the vertex with id id).
+func (d *DAG) GetAncestorsGraphMaxDepth(id string, maxDepth int) (*DAG, string, error) {
+	return d.getRelativesGraphMaxDepth(id, maxDepth, true)
+}
+
+func (d *DAG) getRelativesGraphMaxDepth(id string, maxDepth int, asc bool) (*DAG, string, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if err := d.saneID(id); err != nil {
+		return nil, "", err
 	}
-	v, exists := d.vertexIds[id]
-	vHash := d.hashVertex(v)
-	if !exists {
-		return nil, "", IDUnknownError{id}
+	edges := d.outboundEdge
+	if asc {
+		edges = d.inboundEdge
+	}
+
+	// breadth first, such that each vertex is reached via the shortest path
+	vHash := d.hashVertex(d.vertexIds[id])
+	hashes := map[interface{}]struct{}{vHash: {}}
+	reached := []interface{}{vHash}
+	level := []interface{}{vHash}
+	for depth := 0; depth < maxDepth && len(level) > 0; depth++ {
+		var next []interface{}
+		for _, top := range level {
+			for relative := range edges[top] {
+				if _, exists := hashes[relative]; !exists {
+					hashes[relative] = struct{}{}
+					next = append(next, relative)
+				}
+			}
+		}
+		reached = append(reached, next...)
+		level = next
 	}
 
-	// create a new dag
+	// copy the reached vertices and all edges between them (like
+	// getRelativesGraph does)
 	newDAG := NewDAG()
+	newIDs := make(map[interface{}]string, len(reached))
+	for _, relative := range reached {
+		newID, err := newDAG.AddVertex(relative)
+		if err != nil {
+			return nil, "", err
+		}
+		newIDs[relative] = newID
+	}
+	for _, src := range reached {
+		for dst := range d.outboundEdge[src] {
+			if dstID, exists := newIDs[dst]; exists {
+				if err := newDAG.AddEdge(newIDs[src], dstID); err != nil {
+					return nil, "", err
+				}
+			}
+		}
+	}
+	newHashes := make(map[interface{}]interface{}, len(newIDs))
+	for relative, newID := range newIDs {
+		newHashes[relative] = newDAG.hashVertex(newDAG.vertexIds[newID])
+	}
+	d.copyEdgeAttributes(newDAG, newHashes)
+	return newDAG, newIDs[vHash], nil
+}
+
+func (d *DAG) getRelativesGraph(id string, asc bool) (*DAG, string, error) {
 
 	// protect the graph from modification
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
 
+	// sanity checking
+	if err := d.saneID(id); err != nil {
+		return nil, "", err
+	}
+	v := d.vertexIds[id]
+	vHash := d.hashVertex(v)
+
+	// create a new dag
+	newDAG := NewDAG()
+
 	// recursively add the current vertex and all its relatives
-	newId, err := d.getRelativesGraphRec(vHash, newDAG, make(map[interface{}]string), asc)
-	return newDAG, newId, err
+	visited := make(map[interface{}]string)
+	newId, err := d.getRelativesGraphRec(vHash, newDAG, visited, asc)
+	if err != nil {
+		return newDAG, newId, err
+	}
+
+	// copy the weights and labels of the edges (the relatives graph contains
+	// all edges between the relatives)
+	newHashes := make(map[interface{}]interface{}, len(visited))
+	for relative, relativeID := range visited {
+		newHashes[relative] = newDAG.hashVertex(newDAG.vertexIds[relativeID])
+	}
+	d.copyEdgeAttributes(newDAG, newHashes)
+	return newDAG, newId, nil
 }
 
 func (d *DAG) getRelativesGraphRec(vHash interface{}, newDAG *DAG, visited map[interface{}]string, asc bool) (newId string, err error) {
@@ -840,6 +2088,10 @@ func (d *DAG) getRelativesGraphRec(vHash interface{}, newDAG *DAG, visited map[i
 //
 // Note, there is no order between sibling vertices. Two consecutive runs of
 // DescendantsWalker may return different results.
+//
+// Note, the signal channel is never closed. Thus, it is safe to send a signal
+// at any time (also after the last vertex has been returned), as long as at
+// most one signal is sent.
 func (d *DAG) DescendantsWalker(id string) (chan string, chan bool, error) {
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
@@ -852,15 +2104,57 @@ func (d *DAG) DescendantsWalker(id string) (chan string, chan bool, error) {
 		d.muDAG.RLock()
 		v := d.vertexIds[id]
 		vHash := d.hashVertex(v)
-		d.walkDescendants(vHash, ids, signal)
+		d.walkDescendants(vHash, func(top interface{}) bool {
+			select {
+			case <-signal:
+				return false
+			case ids <- d.vertices[top]:
+				return true
+			}
+		})
 		d.muDAG.RUnlock()
 		close(ids)
-		close(signal)
 	}()
 	return ids, signal, nil
 }
 
-func (d *DAG) walkDescendants(vHash interface{}, ids chan string, signal chan bool) {
+// DescendantsChannelV works like DescendantsWalker, but the returned channel
+// yields the (storable) vertices (i.e. the id and the value) instead of only
+// the ids. Thus, there is no need to (lock the graph and) call GetVertex for
+// each returned id.
+//
+// Note, the same as for DescendantsWalker applies to the signal channel.
+func (d *DAG) DescendantsChannelV(id string) (chan Vertexer, chan bool, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(id); err != nil {
+		return nil, nil, err
+	}
+	vertices := make(chan Vertexer)
+	signal := make(chan bool, 1)
+	go func() {
+		d.muDAG.RLock()
+		v := d.vertexIds[id]
+		vHash := d.hashVertex(v)
+		d.walkDescendants(vHash, func(top interface{}) bool {
+			topID := d.vertices[top]
+			select {
+			case <-signal:
+				return false
+			case vertices <- storableVertex{WrappedID: topID, Value: d.vertexIds[topID]}:
+				return true
+			}
+		})
+		d.muDAG.RUnlock()
+		close(vertices)
+	}()
+	return vertices, signal, nil
+}
+
+// walkDescendants walks all descendants of the vertex with the hash vHash in
+// a breath first order and calls send for each of them. The walk stops, if
+// send returns false.
+func (d *DAG) walkDescendants(vHash interface{}, send func(top interface{}) bool) {
 	var fifo []interface{}
 	visited := make(map[interface{}]struct{})
 	for child := range d.outboundEdge[vHash] {
@@ -879,16 +2173,14 @@ func (d *DAG) walkDescendants(vHash interface{}, ids chan string, signal chan bo
 				fifo = append(fifo, child)
 			}
 		}
-		select {
-		case <-signal:
+		if !send(top) {
 			return
-		default:
-			ids <- d.vertices[top]
 		}
 	}
 }
 
-// FlowResult describes the data to be passed between vertices in a DescendantsFlow.
+// FlowResult describes the data to be passed between vertices in a
+// DescendantsFlow or an AncestorsFlow.
 type FlowResult struct {
 
 	// The id of the vertex that produced this result.
@@ -897,10 +2189,32 @@ type FlowResult struct {
 	// The actual result.
 	Result interface{}
 
-	// Any error. Note, DescendantsFlow does not care about this error. It is up to
-	// the FlowCallback of downstream vertices to handle the error as needed - if
-	// needed.
+	// Any error. Note, by default DescendantsFlow does not care about this error.
+	// It is up to the FlowCallback of downstream vertices to handle the error as
+	// needed - if needed. See FlowErrorMode for alternatives.
 	Error error
+
+	// The weight of the edge over which this result has been passed (i.e. the
+	// edge between the vertex that produced this result and the vertex that
+	// receives it, see GetEdgeWeight). EdgeWeight is 0 for inputs and for the
+	// results returned by the flow.
+	EdgeWeight float64
+
+	// The label of the edge over which this result has been passed (see
+	// GetEdgeLabel). EdgeLabel is nil for inputs and for the results returned
+	// by the flow.
+	EdgeLabel interface{}
+}
+
+// ResultsByID returns a map of the ids of the given results to their (actual)
+// results. If there are multiple results with the same id, the last one wins.
+// Errors are not part of the map.
+func ResultsByID(results []FlowResult) map[string]interface{} {
+	byID := make(map[string]interface{}, len(results))
+	for _, r := range results {
+		byID[r.ID] = r.Result
+	}
+	return byID
 }
 
 // FlowCallback is the signature of the (callback-) function to call for each
@@ -908,93 +2222,288 @@ type FlowResult struct {
 // work. The parameters of the function are the (complete) DAG, the current
 // vertex ID, and the results of all its parents. An instance of FlowCallback
 // should return a result or an error.
+//
+// Within an AncestorsFlow the roles of parents and children are swapped (i.e.
+// parentResults holds the results of all children).
 type FlowCallback func(d *DAG, id string, parentResults []FlowResult) (interface{}, error)
 
+// FlowCallbackV is like FlowCallback but additionally gets passed the value of
+// the current vertex (see DescendantsFlowV).
+type FlowCallbackV func(d *DAG, id string, value interface{}, parentResults []FlowResult) (interface{}, error)
+
+// FlowErrorMode describes how a flow handles errors returned by (callback-)
+// functions.
+type FlowErrorMode int
+
+const (
+	// FlowErrorsIgnore passes errors to downstream vertices (as part of the
+	// FlowResult) but otherwise ignores them. This is the default.
+	FlowErrorsIgnore FlowErrorMode = iota
+
+	// FlowErrorsFailFast stops the flow on the first error. No further (callback-)
+	// functions are executed (already running ones are allowed to finish) and the
+	// first error is returned (without any results).
+	FlowErrorsFailFast
+
+	// FlowErrorsCollect runs the flow to completion (like FlowErrorsIgnore) and
+	// returns the results along with all errors collected in a FlowErrors.
+	FlowErrorsCollect
+)
+
+// FlowOptions is the configuration for a flow (see DescendantsFlowWithOptions).
+type FlowOptions struct {
+
+	// MaxConcurrency limits the number of (callback-) functions executed
+	// concurrently. A value of 0 or less means unbounded.
+	MaxConcurrency int
+
+	// ErrorMode defines how errors returned by (callback-) functions are handled.
+	ErrorMode FlowErrorMode
+}
+
 // DescendantsFlow traverses descendants of the vertex with the ID startID. For
 // the vertex itself and each of its descendant it executes the given (callback-)
 // function providing it the results of its respective parents. The (callback-)
 // function is only executed after all parents have finished their work.
+//
+// The (callback-) function of the start vertex receives the given inputs (in
+// the given order) as parentResults. Thus, inputs may be used to inject initial
+// state into the flow. If inputs is nil, parentResults of the start vertex is
+// empty.
+//
+// Note, only parents that are part of the flow (i.e. the start vertex and its
+// descendants) are awaited.
 func (d *DAG) DescendantsFlow(startID string, inputs []FlowResult, callback FlowCallback) ([]FlowResult, error) {
+	return d.flow(context.Background(), []string{startID}, inputs, callback, false, FlowOptions{})
+}
+
+// DescendantsFlowWithOptions is like DescendantsFlow but additionally takes
+// FlowOptions (e.g. to limit the number of concurrently executed (callback-)
+// functions).
+func (d *DAG) DescendantsFlowWithOptions(startID string, inputs []FlowResult, callback FlowCallback, options FlowOptions) ([]FlowResult, error) {
+	return d.flow(context.Background(), []string{startID}, inputs, callback, false, options)
+}
+
+// DescendantsFlowContext is like DescendantsFlow but may be canceled via the
+// given context. The context is checked before executing the (callback-)
+// function for each vertex. If the context is canceled (or times out), no
+// further (callback-) functions are executed and DescendantsFlowContext returns
+// the error of the context. Already running (callback-) functions are allowed to
+// finish.
+func (d *DAG) DescendantsFlowContext(ctx context.Context, startID string, inputs []FlowResult, callback FlowCallback) ([]FlowResult, error) {
+	return d.flow(ctx, []string{startID}, inputs, callback, false, FlowOptions{})
+}
+
+// DescendantsFlowMulti is like DescendantsFlow but starts at multiple
+// vertices. Each of the start vertices receives the given inputs and the flow
+// covers the descendants of all of them. A vertex reachable from multiple start
+// vertices is still processed exactly once (after all its parents within the
+// flow have finished their work). DescendantsFlowMulti returns an error, if any
+// of the startIDs is empty or unknown.
+//
+// Note, a start vertex that is a descendant of another start vertex receives
+// the results of its parents in addition to the inputs.
+func (d *DAG) DescendantsFlowMulti(startIDs []string, inputs []FlowResult, callback FlowCallback) ([]FlowResult, error) {
+	return d.flow(context.Background(), startIDs, inputs, callback, false, FlowOptions{})
+}
+
+// DescendantsFlowV is like DescendantsFlow but passes the value of the
+// respective vertex to the (callback-) function. Thus, the (callback-) function
+// doesn't need to call GetVertex (which would acquire the lock of the DAG again).
+func (d *DAG) DescendantsFlowV(startID string, inputs []FlowResult, callback FlowCallbackV) ([]FlowResult, error) {
+	withValue := func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		return callback(d, id, d.vertexIds[id], parentResults)
+	}
+	return d.flow(context.Background(), []string{startID}, inputs, withValue, false, FlowOptions{})
+}
+
+// AncestorsFlow traverses ancestors of the vertex with the ID startID. For the
+// vertex itself and each of its ancestors it executes the given (callback-)
+// function providing it the results of its respective children (as
+// parentResults). The (callback-) function is only executed after all children
+// have finished their work. AncestorsFlow returns the results of the roots of
+// the traversed subgraph. Like with DescendantsFlow, the start vertex receives
+// the given inputs as parentResults.
+//
+// Note, only children that are part of the flow (i.e. the start vertex and its
+// ancestors) are awaited.
+func (d *DAG) AncestorsFlow(startID string, inputs []FlowResult, callback FlowCallback) ([]FlowResult, error) {
+	return d.flow(context.Background(), []string{startID}, inputs, callback, true, FlowOptions{})
+}
+
+func (d *DAG) flow(ctx context.Context, startIDs []string, inputs []FlowResult, callback FlowCallback, asc bool, options FlowOptions) ([]FlowResult, error) {
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
 
-	// Get IDs of all descendant vertices.
-	flowIDs, errDes := d.GetDescendants(startID)
-	if errDes != nil {
-		return []FlowResult{}, errDes
+	// Get IDs of all relative vertices (depending on the direction either
+	// ancestors or descendants) of all start vertices.
+	flowIDs := make(map[string]interface{})
+	starts := make(map[string]struct{}, len(startIDs))
+	for _, startID := range startIDs {
+		var relatives map[string]interface{}
+		var errRel error
+		if asc {
+			relatives, errRel = d.getAncestorsByID(startID)
+		} else {
+			relatives, errRel = d.getDescendantsByID(startID)
+		}
+		if errRel != nil {
+			return []FlowResult{}, errRel
+		}
+		for id := range relatives {
+			flowIDs[id] = struct{}{}
+		}
+		starts[startID] = struct{}{}
+	}
+
+	// To also process the start vertices and to have their results being passed
+	// to their successors, add them to the vertex IDs.
+	for startID := range starts {
+		flowIDs[startID] = struct{}{}
 	}
 
-	// inputChannels provides for input channels for each of the descendant vertices (+ the start-vertex).
-	inputChannels := make(map[string]chan FlowResult, len(flowIDs)+1)
+	// inputChannels provides for input channels for each of the relative vertices (+ the start-vertex).
+	inputChannels := make(map[string]chan FlowResult, len(flowIDs))
+
+	// successors holds the vertices (within the flow) to notify for each vertex
+	// (depending on the direction either parents or children).
+	successors := make(map[string]map[string]interface{}, len(flowIDs))
+
+	// hashes holds the hash of each vertex (to look up edge attributes).
+	hashes := make(map[string]interface{}, len(flowIDs))
 
 	// Iterate vertex IDs and create an input channel for each of them and a single
-	// output channel for leaves. Note, this "pre-flight" is needed to ensure we
-	// really have an input channel regardless of how we traverse the tree and spawn
-	// workers.
-	leafCount := 0
-	if len(flowIDs) == 0 {
-		leafCount = 1
-	}
+	// output channel for the last vertices (i.e. vertices without successors).
+	// Note, this "pre-flight" is needed to ensure we really have an input channel
+	// regardless of how we traverse the tree and spawn workers.
+	lastCount := 0
 	for id := range flowIDs {
 
-		// Get all parents of this vertex.
-		parents, errPar := d.GetParents(id)
-		if errPar != nil {
-			return []FlowResult{}, errPar
+		// Get all predecessors and successors of this vertex. Note, we collect all
+		// of them before spawning any goroutine to be able to release the read lock
+		// as early as possible.
+		var predecessors map[string]interface{}
+		var errPre, errSuc error
+		if asc {
+			predecessors, errPre = d.getChildren(id)
+			successors[id], errSuc = d.getParents(id)
+		} else {
+			predecessors, errPre = d.getParents(id)
+			successors[id], errSuc = d.getChildren(id)
+		}
+		if errPre != nil {
+			return []FlowResult{}, errPre
+		}
+		if errSuc != nil {
+			return []FlowResult{}, errSuc
 		}
 
-		// Create a buffered input channel that has capacity for all parent results.
-		inputChannels[id] = make(chan FlowResult, len(parents))
+		// Create a buffered input channel that has capacity for all predecessor
+		// results (plus all inputs for start vertices). Predecessors not being
+		// part of the flow will never deliver any results.
+		predecessorCount := 0
+		for predecessor := range predecessors {
+			if _, exists := flowIDs[predecessor]; exists {
+				predecessorCount++
+			}
+		}
+		if _, exists := starts[id]; exists {
+			predecessorCount += len(inputs)
+		}
+		inputChannels[id] = make(chan FlowResult, predecessorCount)
+		hashes[id] = d.hashVertex(d.vertexIds[id])
 
-		if d.isLeaf(id) {
-			leafCount += 1
+		if len(successors[id]) == 0 {
+			lastCount += 1
 		}
 	}
 
-	// outputChannel caries the results of leaf vertices.
-	outputChannel := make(chan FlowResult, leafCount)
+	// outputChannel caries the results of the last vertices.
+	outputChannel := make(chan FlowResult, lastCount)
 
-	// To also process the start vertex and to have its results being passed to its
-	// children, add it to the vertex IDs. Also add an input channel for the start
-	// vertex and feed the inputs to this channel.
-	flowIDs[startID] = struct{}{}
-	inputChannels[startID] = make(chan FlowResult, len(inputs))
-	for _, i := range inputs {
-		inputChannels[startID] <- i
+	// Feed the inputs to the input channels of the start vertices.
+	for startID := range starts {
+		for _, i := range inputs {
+			inputChannels[startID] <- i
+		}
 	}
 
 	wg := sync.WaitGroup{}
 
-	// Iterate all vertex IDs (now incl. start vertex) and handle each worker (incl.
+	// Allow the flow to be stopped on the first error and keep track of errors
+	// (if requested).
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var muErrors sync.Mutex
+	var errs []error
+
+	// semaphore limits the number of concurrently executed workers (if requested).
+	var semaphore chan struct{}
+	if options.MaxConcurrency > 0 {
+		semaphore = make(chan struct{}, options.MaxConcurrency)
+	}
+
+	// Iterate all vertex IDs (incl. start vertex) and handle each worker (incl.
 	// inputs and outputs) in a separate goroutine.
 	for id := range flowIDs {
 
-		// Get all children of this vertex that later need to be notified. Note, we
-		// collect all children before the goroutine to be able to release the read
-		// lock as early as possible.
-		children, errChildren := d.GetChildren(id)
-		if errChildren != nil {
-			return []FlowResult{}, errChildren
-		}
-
 		// Remember to wait for this goroutine.
 		wg.Add(1)
 
 		go func(id string) {
 
+			// "Sign off" when done.
+			defer wg.Done()
+
 			// Get this vertex's input channel.
 			// Note, only concurrent read here, which is fine.
 			c := inputChannels[id]
 
-			// Await all parent inputs and stuff them into a slice.
-			parentCount := cap(c)
-			parentResults := make([]FlowResult, parentCount)
-			for i := 0; i < parentCount; i++ {
-				parentResults[i] = <-c
+			// Await all predecessor inputs and stuff them into a slice. Stop awaiting, if
+			// the flow has been canceled.
+			predecessorCount := cap(c)
+			predecessorResults := make([]FlowResult, predecessorCount)
+			for i := 0; i < predecessorCount; i++ {
+				select {
+				case predecessorResults[i] = <-c:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			// Acquire a slot to execute the worker (if the concurrency is limited).
+			if semaphore != nil {
+				select {
+				case semaphore <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			// Don't start the worker, if the flow has been canceled meanwhile.
+			if ctx.Err() != nil {
+				if semaphore != nil {
+					<-semaphore
+				}
+				return
 			}
 
 			// Execute the worker.
-			result, errWorker := callback(d, id, parentResults)
+			result, errWorker := callback(d, id, predecessorResults)
+			if semaphore != nil {
+				<-semaphore
+			}
+
+			// Handle the worker's error (depending on the error mode).
+			if errWorker != nil && options.ErrorMode != FlowErrorsIgnore {
+				muErrors.Lock()
+				errs = append(errs, errWorker)
+				muErrors.Unlock()
+				if options.ErrorMode == FlowErrorsFailFast {
+					cancel()
+					return
+				}
+			}
 
 			// Wrap the worker's result into a FlowResult.
 			flowResult := FlowResult{
@@ -1003,50 +2512,236 @@ func (d *DAG) DescendantsFlow(startID string, inputs []FlowResult, callback Flow
 				Error:  errWorker,
 			}
 
-			// Send this worker's FlowResult onto all children's input channels or, if it is
-			// a leaf (i.e. no children), send the result onto the output channel.
-			if len(children) > 0 {
-				for child := range children {
-					inputChannels[child] <- flowResult
+			// Send this worker's FlowResult (along with the attributes of the
+			// respective edge) onto all successors' input channels or, if there
+			// are no successors, send the result onto the output channel.
+			if len(successors[id]) > 0 {
+				for successor := range successors[id] {
+					srcHash, dstHash := hashes[id], hashes[successor]
+					if asc {
+						srcHash, dstHash = dstHash, srcHash
+					}
+					edgeResult := flowResult
+					edgeResult.EdgeWeight = d.edgeWeight(srcHash, dstHash)
+					edgeResult.EdgeLabel = d.edgeLabels[srcHash][dstHash]
+					inputChannels[successor] <- edgeResult
 				}
 			} else {
 				outputChannel <- flowResult
 			}
 
-			// "Sign off".
-			wg.Done()
-
 		}(id)
 	}
 
 	// Wait for all go routines to finish.
 	wg.Wait()
 
-	// Await all leaf vertex results and stuff them into a slice.
+	if options.ErrorMode == FlowErrorsFailFast && len(errs) > 0 {
+		return []FlowResult{}, errs[0]
+	}
+	if err := ctx.Err(); err != nil {
+		return []FlowResult{}, err
+	}
+
+	// Await all last vertex results and stuff them into a slice.
 	resultCount := cap(outputChannel)
 	results := make([]FlowResult, resultCount)
 	for i := 0; i < resultCount; i++ {
 		results[i] = <-outputChannel
 	}
 
+	if options.ErrorMode == FlowErrorsCollect {
+		return results, joinFlowErrors(errs)
+	}
 	return results, nil
 }
 
+// LayeredFlow executes the given (callback-) function for all vertices of the
+// graph level by level (see TopologicalLevels). That is, the (callback-)
+// functions of all vertices of a level are executed concurrently and
+// LayeredFlow waits for all of them to finish before starting with the next
+// level. Like with DescendantsFlow, each (callback-) function is provided the
+// results of its parents (ordered by the ids of the parents). LayeredFlow
+// returns the results of all leaves ordered by their ids.
+//
+// In contrast to DescendantsFlow, a vertex may have to wait for vertices it
+// doesn't depend on (i.e. the barrier between levels). In return, the stages
+// of the flow are predictable.
+func (d *DAG) LayeredFlow(callback FlowCallback) ([]FlowResult, error) {
+	return d.LayeredFlowWithOptions(callback, FlowOptions{})
+}
+
+// LayeredFlowWithOptions is like LayeredFlow but additionally takes
+// FlowOptions (e.g. to limit the number of concurrently executed (callback-)
+// functions within a level). With FlowErrorsFailFast, no further level is
+// started after a (callback-) function returned an error.
+func (d *DAG) LayeredFlowWithOptions(callback FlowCallback, options FlowOptions) ([]FlowResult, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	// semaphore limits the number of concurrently executed workers (if requested).
+	var semaphore chan struct{}
+	if options.MaxConcurrency > 0 {
+		semaphore = make(chan struct{}, options.MaxConcurrency)
+	}
+
+	results := make(map[string]FlowResult, len(d.vertexIds))
+	var errs []error
+	for _, level := range d.topologicalLevels() {
+
+		// Execute the workers of this level and wait for all of them.
+		levelResults := make([]FlowResult, len(level))
+		wg := sync.WaitGroup{}
+		for i, id := range level {
+
+			// Collect the results of the parents (i.e. of previous levels) along
+			// with the attributes of the respective edges.
+			vHash := d.hashVertex(d.vertexIds[id])
+			parents, _ := d.getParents(id)
+			parentResults := make([]FlowResult, 0, len(parents))
+			for _, parentID := range vertexIDs(parents) {
+				parentHash := d.hashVertex(d.vertexIds[parentID])
+				edgeResult := results[parentID]
+				edgeResult.EdgeWeight = d.edgeWeight(parentHash, vHash)
+				edgeResult.EdgeLabel = d.edgeLabels[parentHash][vHash]
+				parentResults = append(parentResults, edgeResult)
+			}
+
+			wg.Add(1)
+			go func(i int, id string, parentResults []FlowResult) {
+				defer wg.Done()
+				if semaphore != nil {
+					semaphore <- struct{}{}
+					defer func() { <-semaphore }()
+				}
+				result, errWorker := callback(d, id, parentResults)
+				levelResults[i] = FlowResult{ID: id, Result: result, Error: errWorker}
+			}(i, id, parentResults)
+		}
+		wg.Wait()
+
+		// Handle the workers' errors (depending on the error mode).
+		for _, r := range levelResults {
+			results[r.ID] = r
+			if r.Error != nil && options.ErrorMode != FlowErrorsIgnore {
+				errs = append(errs, r.Error)
+			}
+		}
+		if options.ErrorMode == FlowErrorsFailFast && len(errs) > 0 {
+			return []FlowResult{}, errs[0]
+		}
+	}
+
+	leafResults := make([]FlowResult, 0)
+	for _, id := range vertexIDs(d.getLeaves()) {
+		leafResults = append(leafResults, results[id])
+	}
+	if options.ErrorMode == FlowErrorsCollect {
+		return leafResults, joinFlowErrors(errs)
+	}
+	return leafResults, nil
+}
+
+// TopologicalSort returns the ids of all vertices in a topological order (i.e.
+// for any edge a -> b, a comes before b). The order is computed using Kahn's
+// algorithm. Ties between vertices that are ready at the same time are broken
+// by sorting their ids, so the order is stable across runs. TopologicalSort
+// only returns an error, if the graph is inconsistent.
+func (d *DAG) TopologicalSort() ([]string, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.topologicalSort()
+}
+
+func (d *DAG) topologicalSort() ([]string, error) {
+
+	// count the parents of each vertex and collect the vertices without any
+	inDegree := make(map[interface{}]int, len(d.vertices))
+	var ready []string
+	for vHash, id := range d.vertices {
+		inDegree[vHash] = len(d.inboundEdge[vHash])
+		if inDegree[vHash] == 0 {
+			ready = append(ready, id)
+		}
+	}
+	sort.Strings(ready)
+
+	sorted := make([]string, 0, len(d.vertices))
+	for len(ready) > 0 {
+		id := ready[0]
+		ready = ready[1:]
+		sorted = append(sorted, id)
+
+		// "remove" all outbound edges and remember the children that are ready now
+		vHash := d.hashVertex(d.vertexIds[id])
+		for child := range d.outboundEdge[vHash] {
+			inDegree[child]--
+			if inDegree[child] == 0 {
+				childID := d.vertices[child]
+				i := sort.SearchStrings(ready, childID)
+				ready = append(ready, "")
+				copy(ready[i+1:], ready[i:])
+				ready[i] = childID
+			}
+		}
+	}
+
+	if len(sorted) != len(d.vertices) {
+		return nil, fmt.Errorf("graph is inconsistent, sorted %d of %d vertices", len(sorted), len(d.vertices))
+	}
+	return sorted, nil
+}
+
+// TransitiveReductionCopy returns a transitively reduced copy of the graph
+// (see ReduceTransitively) leaving the graph itself unchanged. The copy has the
+// same options, ids, and values. The weights and labels of the remaining edges
+// are kept.
+func (d *DAG) TransitiveReductionCopy() (*DAG, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	newDAG, err := d.copyWithIDs(keepValue, d.edgeIDs())
+	if err != nil {
+		return nil, err
+	}
+	newHashes := make(map[interface{}]interface{}, len(d.vertices))
+	for vHash, id := range d.vertices {
+		newHashes[vHash] = newDAG.hashVertex(newDAG.vertexIds[id])
+	}
+	d.copyEdgeAttributes(newDAG, newHashes)
+
+	newDAG.ReduceTransitively()
+	return newDAG, nil
+}
+
 // ReduceTransitively transitively reduce the graph.
 //
 // Note, in order to do the reduction the descendant-cache of all vertices is
 // populated (i.e. the transitive closure). Depending on order and size of DAG
 // this may take a long time and consume a lot of memory.
 func (d *DAG) ReduceTransitively() {
+	d.ReduceTransitivelyRemoved()
+}
+
+// ReduceTransitivelyRemoved is like ReduceTransitively but returns the removed
+// edges (i.e. pairs of srcID and dstID) ordered by srcID and dstID. If the
+// graph already is transitively reduced, the returned slice is empty.
+func (d *DAG) ReduceTransitivelyRemoved() (removed [][2]string) {
 
 	d.muDAG.Lock()
-	defer d.muDAG.Unlock()
+	defer d.unlockAndEmit()
+
+	if d.frozen {
+		return [][2]string{}
+	}
 
-	graphChanged := false
+	removed = make([][2]string, 0)
 
 	// populate the descendents cache for all roots (i.e. the whole graph)
-	for _, root := range d.getRoots() {
-		_ = d.getDescendants(root)
+	if !d.options.DisableCaching {
+		for _, root := range d.getRoots() {
+			_ = d.getDescendants(root)
+		}
 	}
 
 	// for each vertex
@@ -1059,7 +2754,7 @@ func (d *DAG) ReduceTransitively() {
 		for childOfV := range d.outboundEdge[vHash] {
 
 			// collect child descendants
-			for descendent := range d.descendantsCache[childOfV] {
+			for descendent := range d.getDescendants(childOfV) {
 				descendentsOfChildrenOfV[descendent] = struct{}{}
 			}
 		}
@@ -1070,17 +2765,39 @@ func (d *DAG) ReduceTransitively() {
 			// remove the edge between v and child, iff child is a
 			// descendant of any of the children of v
 			if _, exists := descendentsOfChildrenOfV[childOfV]; exists {
-				delete(d.outboundEdge[vHash], childOfV)
-				delete(d.inboundEdge[childOfV], vHash)
-				graphChanged = true
+				d.removeEdge(vHash, childOfV)
+				removed = append(removed, [2]string{d.vertices[vHash], d.vertices[childOfV]})
 			}
 		}
 	}
 
 	// flush the descendants- and ancestor cache if the graph has changed
-	if graphChanged {
+	if len(removed) > 0 {
 		d.flushCaches()
 	}
+
+	sortEdges(removed)
+	return removed
+}
+
+// Freeze makes the graph immutable. Afterwards, all methods that modify the
+// vertices (including their metadata), the edges, or the attributes of the
+// edges (e.g. AddVertex, AddEdge, or SetEdgeLabel) return a FrozenError (see ErrFrozen) and
+// ReduceTransitively doesn't change the graph. Queries keep working. Thus,
+// properties computed from a frozen graph (e.g. its depths or levels) may be
+// memoized safely. Freezing can't be undone, but copies of a frozen graph
+// (e.g. via Copy) are not frozen.
+func (d *DAG) Freeze() {
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+	d.frozen = true
+}
+
+// IsFrozen returns true, if the graph is frozen (see Freeze).
+func (d *DAG) IsFrozen() bool {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.frozen
 }
 
 // FlushCaches completely flushes the descendants- and ancestor cache.
@@ -1096,9 +2813,14 @@ func (d *DAG) FlushCaches() {
 func (d *DAG) flushCaches() {
 	d.ancestorsCache = make(map[interface{}]map[interface{}]struct{})
 	d.descendantsCache = make(map[interface{}]map[interface{}]struct{})
+	d.ancestorsLRU = newCacheLRU()
+	d.descendantsLRU = newCacheLRU()
 }
 
-// Copy returns a copy of the DAG.
+// Copy returns a copy of the DAG. The weights and labels of edges are copied
+// as well, such that changing them in the copy doesn't affect the original
+// (and vice versa). Note, labels themselves are not copied deeply (i.e. a
+// label that is e.g. a pointer or a map is shared by both graphs).
 func (d *DAG) Copy() (newDAG *DAG, err error) {
 
 	// create a new dag
@@ -1112,26 +2834,243 @@ func (d *DAG) Copy() (newDAG *DAG, err error) {
 	defer d.muDAG.RUnlock()
 
 	// add all roots and their descendants to the new DAG
-	for _, root := range d.GetRoots() {
+	for _, root := range d.getRoots() {
 		if _, err = d.getRelativesGraphRec(root, newDAG, visited, false); err != nil {
 			return
 		}
 	}
+
+	// copy the edge attributes
+	newHashes := make(map[interface{}]interface{}, len(visited))
+	for vHash, newID := range visited {
+		newHashes[vHash] = newDAG.hashVertex(newDAG.vertexIds[newID])
+	}
+	d.copyEdgeAttributes(newDAG, newHashes)
+	return
+}
+
+// CopyWithTransform returns a copy of the DAG with the same ids and edges but
+// where the value of each vertex is replaced by the result of fn. The options
+// of the DAG are copied as well. CopyWithTransform returns an error, if fn
+// returns nil or the same value (in terms of the VertexHashFunc) for different
+// vertices.
+func (d *DAG) CopyWithTransform(fn func(id string, v interface{}) interface{}) (*DAG, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	return d.copyWithIDs(fn, d.edgeIDs())
+}
+
+// copyWithIDs returns a new DAG with the options and ids of d, where the value
+// of each vertex is given by fn, and with the given edges (i.e. pairs of srcID
+// and dstID). copyWithIDs must be called with the read lock of d held.
+func (d *DAG) copyWithIDs(fn func(id string, v interface{}) interface{}, edges map[[2]string]struct{}) (*DAG, error) {
+	newDAG := NewDAG()
+	newDAG.Options(d.options)
+	for id, v := range d.vertexIds {
+		if err := newDAG.addVertexByID(id, fn(id, v)); err != nil {
+			return nil, err
+		}
+	}
+	for e := range edges {
+		if err := newDAG.addEdge(e[0], e[1]); err != nil {
+			return nil, err
+		}
+	}
+	return newDAG, nil
+}
+
+// keepValue is the transformation (see copyWithIDs) that keeps the values.
+func keepValue(_ string, v interface{}) interface{} {
+	return v
+}
+
+// Equal returns true iff d and other have the same ids, the same edges, and
+// (in terms of reflect.DeepEqual) equal values. Neither the insertion order
+// nor the state of the caches is relevant.
+func (d *DAG) Equal(other *DAG) bool {
+	if d == other {
+		return true
+	}
+	if other == nil {
+		return false
+	}
+
+	// Take the snapshots one after another (instead of holding both read locks
+	// at once), as a.Equal(b) and b.Equal(a) would otherwise lock in different
+	// orders and may deadlock with pending writers.
+	snapshot, otherSnapshot := d.idSnapshot(), other.idSnapshot()
+
+	if len(snapshot.vertices) != len(otherSnapshot.vertices) || len(snapshot.edges) != len(otherSnapshot.edges) {
+		return false
+	}
+	for id, v := range snapshot.vertices {
+		otherV, exists := otherSnapshot.vertices[id]
+		if !exists || !reflect.DeepEqual(v, otherV) {
+			return false
+		}
+	}
+	for e := range snapshot.edges {
+		if _, exists := otherSnapshot.edges[e]; !exists {
+			return false
+		}
+	}
+	return true
+}
+
+// idSnapshot is a copy of the vertices (i.e. ids and values) and the edges
+// (i.e. pairs of srcID and dstID) of a graph.
+type idSnapshot struct {
+	vertices map[string]interface{}
+	edges    map[[2]string]struct{}
+}
+
+// idSnapshot returns a copy of the vertices and edges of d taken under the
+// read lock of d.
+func (d *DAG) idSnapshot() idSnapshot {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	vertices := make(map[string]interface{}, len(d.vertexIds))
+	for id, v := range d.vertexIds {
+		vertices[id] = v
+	}
+	return idSnapshot{vertices: vertices, edges: d.edgeIDs()}
+}
+
+// Diff returns the differences between d and other in terms of vertex ids
+// and edges. That is, the ids of the vertices and the edges (i.e. pairs of
+// srcID and dstID) that are part of other but not of d (added), and those that
+// are part of d but not of other (removed). The values of the vertices are not
+// compared. All results are sorted. A nil other is treated as an empty DAG.
+func (d *DAG) Diff(other *DAG) (addedVertices, removedVertices []string, addedEdges, removedEdges [][2]string) {
+	addedVertices, removedVertices = make([]string, 0), make([]string, 0)
+	addedEdges, removedEdges = make([][2]string, 0), make([][2]string, 0)
+	if d == other {
+		return
+	}
+
+	// see Equal
+	snapshot, otherSnapshot := d.idSnapshot(), idSnapshot{}
+	if other != nil {
+		otherSnapshot = other.idSnapshot()
+	}
+
+	for _, id := range vertexIDs(otherSnapshot.vertices) {
+		if _, exists := snapshot.vertices[id]; !exists {
+			addedVertices = append(addedVertices, id)
+		}
+	}
+	for _, id := range vertexIDs(snapshot.vertices) {
+		if _, exists := otherSnapshot.vertices[id]; !exists {
+			removedVertices = append(removedVertices, id)
+		}
+	}
+
+	for e := range otherSnapshot.edges {
+		if _, exists := snapshot.edges[e]; !exists {
+			addedEdges = append(addedEdges, e)
+		}
+	}
+	for e := range snapshot.edges {
+		if _, exists := otherSnapshot.edges[e]; !exists {
+			removedEdges = append(removedEdges, e)
+		}
+	}
+	sortEdges(addedEdges)
+	sortEdges(removedEdges)
 	return
 }
 
-// String returns a textual representation of the graph.
+// edgeIDs returns the set of all edges as pairs of srcID and dstID.
+func (d *DAG) edgeIDs() map[[2]string]struct{} {
+	edges := make(map[[2]string]struct{}, d.getSize())
+	for srcHash, children := range d.outboundEdge {
+		for dstHash := range children {
+			edges[[2]string{d.vertices[srcHash], d.vertices[dstHash]}] = struct{}{}
+		}
+	}
+	return edges
+}
+
+// sortEdges sorts the given edges by srcID and dstID.
+func sortEdges(edges [][2]string) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i][0] != edges[j][0] {
+			return edges[i][0] < edges[j][0]
+		}
+		return edges[i][1] < edges[j][1]
+	})
+}
+
+// Transpose returns a new DAG with the same vertices (i.e. the same ids and
+// values) but with all edges reversed (i.e. for every edge src -> dst, the new
+// DAG contains the edge dst -> src). Roots of the original graph become leaves
+// of the new graph and vice versa.
+func (d *DAG) Transpose() (*DAG, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	edges := make(map[[2]string]struct{}, d.getSize())
+	for e := range d.edgeIDs() {
+		edges[[2]string{e[1], e[0]}] = struct{}{}
+	}
+	return d.copyWithIDs(keepValue, edges)
+}
+
+// TransitiveClosure returns a new DAG with the same vertices (i.e. the same ids
+// and values) and an edge from each vertex to each of its descendants.
+//
+// Note, the number of edges of the transitive closure is O(V²). Furthermore,
+// in order to compute the transitive closure, the descendant-cache of all
+// vertices is populated. Depending on order and size of DAG this may take a
+// long time and consume a lot of memory.
+func (d *DAG) TransitiveClosure() (*DAG, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	edges := make(map[[2]string]struct{})
+	for vHash, id := range d.vertices {
+		for descendant := range d.getDescendants(vHash) {
+			edges[[2]string{id, d.vertices[descendant]}] = struct{}{}
+		}
+	}
+	return d.copyWithIDs(keepValue, edges)
+}
+
+// ReachablePairs returns all pairs of ids (a, b) such that b is a descendant
+// of a (i.e. the edges of the transitive closure - see TransitiveClosure).
+// Pairs are sorted by a and b.
+//
+// Note, the number of pairs is O(V²) in the worst case. Furthermore, in order
+// to compute the pairs, the descendant-cache of all vertices is populated.
+func (d *DAG) ReachablePairs() [][2]string {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	pairs := make([][2]string, 0)
+	for vHash, id := range d.vertices {
+		for descendant := range d.getDescendants(vHash) {
+			pairs = append(pairs, [2]string{id, d.vertices[descendant]})
+		}
+	}
+	sortEdges(pairs)
+	return pairs
+}
+
+// String returns a textual representation of the graph. Vertices are labeled
+// as of Options.StringFunc.
 func (d *DAG) String() string {
-	result := fmt.Sprintf("DAG Vertices: %d - Edges: %d\n", d.GetOrder(), d.GetSize())
-	result += "Vertices:\n"
 	d.muDAG.RLock()
-	for k := range d.vertices {
-		result += fmt.Sprintf("  %v\n", k)
+	result := fmt.Sprintf("DAG Vertices: %d - Edges: %d\n", d.getOrder(), d.getSize())
+	result += "Vertices:\n"
+	for _, id := range d.vertices {
+		result += fmt.Sprintf("  %s\n", d.vertexString(id))
 	}
 	result += "Edges:\n"
 	for v, children := range d.outboundEdge {
 		for child := range children {
-			result += fmt.Sprintf("  %v -> %v\n", v, child)
+			result += fmt.Sprintf("  %s -> %s\n", d.vertexString(d.vertices[v]), d.vertexString(d.vertices[child]))
 		}
 	}
 	d.muDAG.RUnlock()
@@ -1151,6 +3090,11 @@ func (d *DAG) saneID(id string) error {
 }
 
 func (d *DAG) hashVertex(v interface{}) interface{} {
+	if d.options.Identity == ByPointer {
+		if h, ok := hashPointer(v); ok {
+			return h
+		}
+	}
 	return d.options.VertexHashFunc(v)
 }
 
@@ -1166,6 +3110,25 @@ func copyMap(in map[interface{}]struct{}) map[interface{}]struct{} {
 ********** Errors **********
 ****************************/
 
+// Sentinel errors that may be used with errors.Is to check for the respective
+// error type (e.g. errors.Is(err, ErrIDUnknown) instead of asserting
+// IDUnknownError).
+var (
+	ErrVertexNil           = errors.New("vertex is nil")
+	ErrVertexDuplicate     = errors.New("vertex is already known")
+	ErrVertexUnknown       = errors.New("vertex is unknown")
+	ErrVertexNotComparable = errors.New("vertex is not comparable")
+	ErrIDDuplicate         = errors.New("id is already known")
+	ErrIDEmpty             = errors.New("id is empty")
+	ErrIDUnknown           = errors.New("id is unknown")
+	ErrIDMismatch          = errors.New("id doesn't match")
+	ErrEdgeDuplicate       = errors.New("edge is already known")
+	ErrEdgeUnknown         = errors.New("edge is unknown")
+	ErrEdgeLoop            = errors.New("edge would create a loop")
+	ErrSrcDstEqual         = errors.New("src and dst are equal")
+	ErrFrozen              = errors.New("graph is frozen")
+)
+
 // VertexNilError is the error type to describe the situation, that a nil is
 // given instead of a vertex.
 type VertexNilError struct{}
@@ -1175,6 +3138,11 @@ func (e VertexNilError) Error() string {
 	return "don't know what to do with 'nil'"
 }
 
+// Is reports whether target is ErrVertexNil (see errors.Is).
+func (e VertexNilError) Is(target error) bool {
+	return target == ErrVertexNil
+}
+
 // VertexDuplicateError is the error type to describe the situation, that a
 // given vertex already exists in the graph.
 type VertexDuplicateError struct {
@@ -1186,6 +3154,44 @@ func (e VertexDuplicateError) Error() string {
 	return fmt.Sprintf("'%v' is already known", e.v)
 }
 
+// Is reports whether target is ErrVertexDuplicate (see errors.Is).
+func (e VertexDuplicateError) Is(target error) bool {
+	return target == ErrVertexDuplicate
+}
+
+// VertexUnknownError is the error type to describe the situation, that a given
+// vertex (value) does not exist in the graph.
+type VertexUnknownError struct {
+	v interface{}
+}
+
+// Implements the error interface.
+func (e VertexUnknownError) Error() string {
+	return fmt.Sprintf("'%v' is unknown", e.v)
+}
+
+// Is reports whether target is ErrVertexUnknown (see errors.Is).
+func (e VertexUnknownError) Is(target error) bool {
+	return target == ErrVertexUnknown
+}
+
+// VertexNotComparableError is the error type to describe the situation, that
+// the hash of a given vertex is not comparable (e.g. because the vertex
+// contains a map and no VertexHashFunc is configured, see Options).
+type VertexNotComparableError struct {
+	v interface{}
+}
+
+// Implements the error interface.
+func (e VertexNotComparableError) Error() string {
+	return fmt.Sprintf("'%v' is not comparable", e.v)
+}
+
+// Is reports whether target is ErrVertexNotComparable (see errors.Is).
+func (e VertexNotComparableError) Is(target error) bool {
+	return target == ErrVertexNotComparable
+}
+
 // IDDuplicateError is the error type to describe the situation, that a given
 // vertex id already exists in the graph.
 type IDDuplicateError struct {
@@ -1197,6 +3203,11 @@ func (e IDDuplicateError) Error() string {
 	return fmt.Sprintf("the id '%s' is already known", e.id)
 }
 
+// Is reports whether target is ErrIDDuplicate (see errors.Is).
+func (e IDDuplicateError) Is(target error) bool {
+	return target == ErrIDDuplicate
+}
+
 // IDEmptyError is the error type to describe the situation, that an empty
 // string is given instead of a valid id.
 type IDEmptyError struct{}
@@ -1206,6 +3217,11 @@ func (e IDEmptyError) Error() string {
 	return "don't know what to do with \"\""
 }
 
+// Is reports whether target is ErrIDEmpty (see errors.Is).
+func (e IDEmptyError) Is(target error) bool {
+	return target == ErrIDEmpty
+}
+
 // IDUnknownError is the error type to describe the situation, that a given
 // vertex does not exit in the graph.
 type IDUnknownError struct {
@@ -1217,6 +3233,28 @@ func (e IDUnknownError) Error() string {
 	return fmt.Sprintf("'%s' is unknown", e.id)
 }
 
+// Is reports whether target is ErrIDUnknown (see errors.Is).
+func (e IDUnknownError) Is(target error) bool {
+	return target == ErrIDUnknown
+}
+
+// IDMismatchError is the error type to describe the situation, that the id of
+// a given vertex (as of IDInterface) differs from the expected id.
+type IDMismatchError struct {
+	id  string
+	vID string
+}
+
+// Implements the error interface.
+func (e IDMismatchError) Error() string {
+	return fmt.Sprintf("the id '%s' doesn't match the expected id '%s'", e.vID, e.id)
+}
+
+// Is reports whether target is ErrIDMismatch (see errors.Is).
+func (e IDMismatchError) Is(target error) bool {
+	return target == ErrIDMismatch
+}
+
 // EdgeDuplicateError is the error type to describe the situation, that an edge
 // already exists in the graph.
 type EdgeDuplicateError struct {
@@ -1229,6 +3267,11 @@ func (e EdgeDuplicateError) Error() string {
 	return fmt.Sprintf("edge between '%s' and '%s' is already known", e.src, e.dst)
 }
 
+// Is reports whether target is ErrEdgeDuplicate (see errors.Is).
+func (e EdgeDuplicateError) Is(target error) bool {
+	return target == ErrEdgeDuplicate
+}
+
 // EdgeUnknownError is the error type to describe the situation, that a given
 // edge does not exit in the graph.
 type EdgeUnknownError struct {
@@ -1241,11 +3284,27 @@ func (e EdgeUnknownError) Error() string {
 	return fmt.Sprintf("edge between '%s' and '%s' is unknown", e.src, e.dst)
 }
 
+// Is reports whether target is ErrEdgeUnknown (see errors.Is).
+func (e EdgeUnknownError) Is(target error) bool {
+	return target == ErrEdgeUnknown
+}
+
 // EdgeLoopError is the error type to describe loop errors (i.e. errors that
 // where raised to prevent establishing loops in the graph).
 type EdgeLoopError struct {
 	src string
 	dst string
+
+	// Path holds the ids of the vertices of an existing path from dst to src
+	// (both inclusive). That is, the path that would be closed to a loop by the
+	// rejected edge.
+	Path []string
+}
+
+// edgeLoopError returns an EdgeLoopError for the edge between srcID and dstID
+// including the existing path from dstID to srcID.
+func (d *DAG) edgeLoopError(srcID, dstID string) EdgeLoopError {
+	return EdgeLoopError{src: srcID, dst: dstID, Path: d.shortestPath(dstID, srcID)}
 }
 
 // Implements the error interface.
@@ -1253,6 +3312,11 @@ func (e EdgeLoopError) Error() string {
 	return fmt.Sprintf("edge between '%s' and '%s' would create a loop", e.src, e.dst)
 }
 
+// Is reports whether target is ErrEdgeLoop (see errors.Is).
+func (e EdgeLoopError) Is(target error) bool {
+	return target == ErrEdgeLoop
+}
+
 // SrcDstEqualError is the error type to describe the situation, that src and
 // dst are equal.
 type SrcDstEqualError struct {
@@ -1265,6 +3329,62 @@ func (e SrcDstEqualError) Error() string {
 	return fmt.Sprintf("src ('%s') and dst ('%s') equal", e.src, e.dst)
 }
 
+// Is reports whether target is ErrSrcDstEqual (see errors.Is).
+func (e SrcDstEqualError) Is(target error) bool {
+	return target == ErrSrcDstEqual
+}
+
+// FrozenError is the error type to describe the situation, that a frozen
+// graph is about to be modified (see Freeze).
+type FrozenError struct{}
+
+// Implements the error interface.
+func (e FrozenError) Error() string {
+	return "the graph is frozen"
+}
+
+// Is reports whether target is ErrFrozen (see errors.Is).
+func (e FrozenError) Is(target error) bool {
+	return target == ErrFrozen
+}
+
+// FlowErrors is the error type to describe the situation, that (callback-)
+// functions of a flow returned errors (see FlowErrorsCollect).
+type FlowErrors []error
+
+// Implements the error interface.
+func (e FlowErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Is reports whether any of the collected errors matches target (see
+// errors.Is).
+func (e FlowErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the collected errors.
+func (e FlowErrors) Unwrap() []error {
+	return e
+}
+
+// joinFlowErrors returns errs as FlowErrors, or nil if errs is empty.
+func joinFlowErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return FlowErrors(errs)
+}
+
 /***************************
 ********** dMutex **********
 ****************************/
@@ -1287,30 +3407,35 @@ func newDMutex() *dMutex {
 	}
 }
 
-// Get a lock for instance i
+// Get a lock for instance i.
 func (d *dMutex) lock(i interface{}) {
 
+	// register our interest in the instance mutex (under the global lock) and
+	// wait on the instance mutex afterwards
+	mutex := d.acquire(i)
+	mutex.Lock()
+}
+
+// acquire returns the instance mutex for i after increasing its count (in
+// order to show, that we are interested in this instance mutex, thus no one
+// deletes it). The global lock is released via defer, such that a panic (e.g.
+// due to i not being hashable) doesn't leave the global lock held.
+func (d *dMutex) acquire(i interface{}) *sync.Mutex {
+
 	// acquire global lock
 	d.globalMutex.Lock()
+	defer d.globalMutex.Unlock()
 
 	// if there is no cMutex for i, create it
-	if _, ok := d.mutexes[i]; !ok {
-		d.mutexes[i] = new(cMutex)
+	cm, ok := d.mutexes[i]
+	if !ok {
+		cm = new(cMutex)
+		d.mutexes[i] = cm
 	}
 
-	// increase the count in order to show, that we are interested in this
-	// instance mutex (thus now one deletes it)
-	d.mutexes[i].count++
-
-	// remember the mutex for later
-	mutex := &d.mutexes[i].mutex
-
-	// as the cMutex is there, we have increased the count, and we know the
-	// instance mutex, we can release the global lock
-	d.globalMutex.Unlock()
-
-	// and wait on the instance mutex
-	(*mutex).Lock()
+	// increase the count only after the cMutex is in place
+	cm.count++
+	return &cm.mutex
 }
 
 // Release the lock for instance i.
@@ -1318,20 +3443,19 @@ func (d *dMutex) unlock(i interface{}) {
 
 	// acquire global lock
 	d.globalMutex.Lock()
+	defer d.globalMutex.Unlock()
 
 	// unlock instance mutex
-	d.mutexes[i].mutex.Unlock()
+	cm := d.mutexes[i]
+	cm.mutex.Unlock()
 
 	// decrease the count, as we are no longer interested in this instance
 	// mutex
-	d.mutexes[i].count--
+	cm.count--
 
 	// if we are the last one interested in this instance mutex delete the
 	// cMutex
-	if d.mutexes[i].count == 0 {
+	if cm.count == 0 {
 		delete(d.mutexes, i)
 	}
-
-	// release the global lock
-	d.globalMutex.Unlock()
 }
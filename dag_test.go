@@ -1,11 +1,17 @@
 package dag
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/go-test/deep"
+	"math/rand"
 	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 type iVertex struct{ value int }
@@ -122,6 +128,53 @@ func TestDAG_AddVertex2(t *testing.T) {
 	}
 }
 
+func TestDAG_AddVertices(t *testing.T) {
+	dag := NewDAG()
+	vs := []interface{}{iVertex{1}, iVertex{2}, "3"}
+	ids, err := dag.AddVertices(vs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != len(vs) {
+		t.Fatalf("AddVertices() returned %d ids, want %d", len(ids), len(vs))
+	}
+	for i, id := range ids {
+		if v, _ := dag.GetVertex(id); v != vs[i] {
+			t.Errorf("GetVertex(%s) = %v, want %v", id, v, vs[i])
+		}
+	}
+	if ids[0] != "1" || ids[1] != "2" {
+		t.Errorf("AddVertices() = %v, want ids 1 and 2 first", ids)
+	}
+
+	// duplicate aborts the whole batch
+	_, err = dag.AddVertices([]interface{}{iVertex{4}, iVertex{5}, iVertex{1}})
+	if _, ok := err.(VertexDuplicateError); !ok {
+		t.Errorf("AddVertices() expected VertexDuplicateError, got %T", err)
+	}
+	if order := dag.GetOrder(); order != 3 {
+		t.Errorf("GetOrder() = %d, want 3", order)
+	}
+	if _, err := dag.GetVertex("4"); err == nil {
+		t.Errorf("GetVertex(4) = nil, want %T", IDUnknownError{"4"})
+	}
+
+	// duplicate within the batch
+	_, err = dag.AddVertices([]interface{}{iVertex{6}, iVertex{6}})
+	if _, ok := err.(VertexDuplicateError); !ok {
+		t.Errorf("AddVertices() expected VertexDuplicateError, got %T", err)
+	}
+
+	// nil
+	_, err = dag.AddVertices([]interface{}{iVertex{7}, nil})
+	if _, ok := err.(VertexNilError); !ok {
+		t.Errorf("AddVertices() expected VertexNilError, got %T", err)
+	}
+	if order := dag.GetOrder(); order != 3 {
+		t.Errorf("GetOrder() = %d, want 3", order)
+	}
+}
+
 func TestDAG_AddVertexByID(t *testing.T) {
 	dag := NewDAG()
 
@@ -169,6 +222,77 @@ func TestDAG_AddVertexByID(t *testing.T) {
 	}
 }
 
+func TestDAG_SortedVertexIDs(t *testing.T) {
+	dag := NewDAG()
+	if ids := dag.SortedVertexIDs(); len(ids) != 0 {
+		t.Errorf("SortedVertexIDs() = %v, want []", ids)
+	}
+	for _, id := range []string{"c", "a", "d", "b"} {
+		_ = dag.AddVertexByID(id, id)
+	}
+	expected := []string{"a", "b", "c", "d"}
+	if ids := dag.SortedVertexIDs(); !equal(ids, expected) {
+		t.Errorf("SortedVertexIDs() = %v, want %v", ids, expected)
+	}
+}
+
+func TestDAG_SortedRootAndLeafIDs(t *testing.T) {
+	dag := NewDAG()
+	if ids := dag.SortedRootIDs(); len(ids) != 0 {
+		t.Errorf("SortedRootIDs() = %v, want []", ids)
+	}
+	for _, id := range []string{"f", "c", "a", "e", "d", "b", "g"} {
+		_ = dag.AddVertexByID(id, id)
+	}
+	_ = dag.AddEdge("c", "b")
+	_ = dag.AddEdge("a", "b")
+	_ = dag.AddEdge("f", "d")
+	_ = dag.AddEdge("b", "e")
+	_ = dag.AddEdge("f", "e")
+
+	expected := []string{"a", "c", "f", "g"}
+	if ids := dag.SortedRootIDs(); !equal(ids, expected) {
+		t.Errorf("SortedRootIDs() = %v, want %v", ids, expected)
+	}
+	expected = []string{"d", "e", "g"}
+	if ids := dag.SortedLeafIDs(); !equal(ids, expected) {
+		t.Errorf("SortedLeafIDs() = %v, want %v", ids, expected)
+	}
+}
+
+func TestDAG_GetRootsAndLeavesMap(t *testing.T) {
+	dag := NewDAG()
+	for i := 1; i <= 4; i++ {
+		_ = dag.AddVertexByID(strconv.Itoa(i), fmt.Sprintf("v%d", i))
+	}
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("3", "2")
+
+	roots := dag.GetRootsMap()
+	expected := map[string]interface{}{"1": "v1", "3": "v3", "4": "v4"}
+	if diff := deep.Equal(roots, expected); diff != nil {
+		t.Errorf("GetRootsMap() = %v, want %v", roots, expected)
+	}
+	leaves := dag.GetLeavesMap()
+	expected = map[string]interface{}{"2": "v2", "4": "v4"}
+	if diff := deep.Equal(leaves, expected); diff != nil {
+		t.Errorf("GetLeavesMap() = %v, want %v", leaves, expected)
+	}
+
+	// values rather than hashes
+	hashed := NewDAG()
+	hashed.Options(Options{VertexHashFunc: func(v interface{}) interface{} {
+		return v.(iVertex).ID()
+	}})
+	_, _ = hashed.AddVertex(iVertex{1})
+	if v := hashed.GetRootsMap()["1"]; v != (iVertex{1}) {
+		t.Errorf("GetRootsMap()[1] = %v, want %v", v, iVertex{1})
+	}
+	if v := hashed.GetLeavesMap()["1"]; v != (iVertex{1}) {
+		t.Errorf("GetLeavesMap()[1] = %v, want %v", v, iVertex{1})
+	}
+}
+
 func TestDAG_GetVertex(t *testing.T) {
 	dag := NewDAG()
 	v1 := iVertex{1}
@@ -225,6 +349,109 @@ func TestDAG_GetVertex(t *testing.T) {
 	}
 }
 
+func TestDAG_GetVertexOrDefault(t *testing.T) {
+	dag := getTestWalkDAG()
+	if v := dag.GetVertexOrDefault("1", "default"); v != "v1" {
+		t.Errorf("GetVertexOrDefault(1, default) = %v, want v1", v)
+	}
+	if v := dag.GetVertexOrDefault("foo", "default"); v != "default" {
+		t.Errorf("GetVertexOrDefault(foo, default) = %v, want default", v)
+	}
+	if v := dag.GetVertexOrDefault("", "default"); v != "default" {
+		t.Errorf("GetVertexOrDefault(\"\", default) = %v, want default", v)
+	}
+	if v := dag.GetVertexOrDefault("foo", nil); v != nil {
+		t.Errorf("GetVertexOrDefault(foo, nil) = %v, want nil", v)
+	}
+}
+
+func TestDAG_GetVertexID(t *testing.T) {
+	dag := getTestWalkDAG()
+	if id, exists := dag.GetVertexID("v3"); !exists || id != "3" {
+		t.Errorf("GetVertexID(v3) = %s, %v, want 3, true", id, exists)
+	}
+	if id, exists := dag.GetVertexID("foo"); exists || id != "" {
+		t.Errorf("GetVertexID(foo) = %s, %v, want \"\", false", id, exists)
+	}
+	if id, exists := dag.GetVertexID(nil); exists || id != "" {
+		t.Errorf("GetVertexID(nil) = %s, %v, want \"\", false", id, exists)
+	}
+}
+
+func TestDAG_ReplaceVertexValue(t *testing.T) {
+	dag := NewDAG()
+	v1, _ := dag.AddVertex(iVertex{1})
+	_ = dag.AddVertexByID("2", "2")
+	_ = dag.AddVertexByID("3", "3")
+	_ = dag.AddEdge(v1, "2")
+	_ = dag.AddEdge("2", "3")
+
+	// populate the caches
+	_, _ = dag.GetDescendants(v1)
+	_, _ = dag.GetAncestors("3")
+	ancestorsCacheSize := len(dag.ancestorsCache)
+	descendantsCacheSize := len(dag.descendantsCache)
+
+	if err := dag.ReplaceVertexValue("2", "two"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := dag.GetVertex("2"); v != "two" {
+		t.Errorf("GetVertex(2) = %v, want two", v)
+	}
+	if isEdge, _ := dag.IsEdge(v1, "2"); !isEdge {
+		t.Errorf("IsEdge(v1, 2) = false, want true")
+	}
+	if isEdge, _ := dag.IsEdge("2", "3"); !isEdge {
+		t.Errorf("IsEdge(2, 3) = false, want true")
+	}
+	if len(dag.ancestorsCache) != ancestorsCacheSize {
+		t.Errorf("len(ancestorsCache) = %d, want %d", len(dag.ancestorsCache), ancestorsCacheSize)
+	}
+	if len(dag.descendantsCache) != descendantsCacheSize {
+		t.Errorf("len(descendantsCache) = %d, want %d", len(dag.descendantsCache), descendantsCacheSize)
+	}
+	if _, exists := dag.descendantsCache[iVertex{1}]["two"]; !exists {
+		t.Errorf("descendantsCache[v1][two] = false, want true")
+	}
+	descendants, _ := dag.GetDescendants(v1)
+	if len(descendants) != 2 || descendants["2"] != "two" {
+		t.Errorf("GetDescendants(v1) = %v, want 2 descendants incl. 2: two", descendants)
+	}
+	ancestors, _ := dag.GetAncestors("3")
+	if len(ancestors) != 2 || ancestors["2"] != "two" {
+		t.Errorf("GetAncestors(3) = %v, want 2 ancestors incl. 2: two", ancestors)
+	}
+
+	// same id
+	if err := dag.ReplaceVertexValue(v1, iVertex{1}); err != nil {
+		t.Error(err)
+	}
+
+	// id mismatch
+	errMismatch := dag.ReplaceVertexValue(v1, iVertex{2})
+	if _, ok := errMismatch.(IDMismatchError); !ok {
+		t.Errorf("ReplaceVertexValue(v1, iVertex{2}) expected IDMismatchError, got %T", errMismatch)
+	}
+
+	// duplicate
+	errDuplicate := dag.ReplaceVertexValue("2", "3")
+	if _, ok := errDuplicate.(VertexDuplicateError); !ok {
+		t.Errorf("ReplaceVertexValue(2, 3) expected VertexDuplicateError, got %T", errDuplicate)
+	}
+
+	// nil
+	errNil := dag.ReplaceVertexValue("2", nil)
+	if _, ok := errNil.(VertexNilError); !ok {
+		t.Errorf("ReplaceVertexValue(2, nil) expected VertexNilError, got %T", errNil)
+	}
+
+	// unknown
+	errUnknown := dag.ReplaceVertexValue("foo", "foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("ReplaceVertexValue(foo, foo) expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
 func TestDAG_DeleteVertex(t *testing.T) {
 	dag := NewDAG()
 	v1, _ := dag.AddVertex(iVertex{1})
@@ -321,6 +548,109 @@ func TestDAG_DeleteVertex(t *testing.T) {
 	}
 }
 
+func TestDAG_DeleteVertices(t *testing.T) {
+	dag := getTestPathDAG()
+	_, _ = dag.GetDescendants("1")
+	_, _ = dag.GetAncestors("7")
+
+	// unknown ids delete nothing
+	errUnknown := dag.DeleteVertices([]string{"2", "foo"})
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("DeleteVertices([2, foo]) expected IDUnknownError, got %T", errUnknown)
+	}
+	if !dag.Equal(getTestPathDAG()) {
+		t.Errorf("DeleteVertices([2, foo]) modified the graph")
+	}
+
+	// nil
+	errNil := dag.DeleteVertices([]string{"2", ""})
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("DeleteVertices([2, \"\"]) expected IDEmptyError, got %T", errNil)
+	}
+
+	// same result as deleting one by one (duplicate ids are ignored)
+	ids := []string{"2", "5", "2"}
+	if err := dag.DeleteVertices(ids); err != nil {
+		t.Fatal(err)
+	}
+	expected := getTestPathDAG()
+	_ = expected.DeleteVertex("2")
+	_ = expected.DeleteVertex("5")
+	if !dag.Equal(expected) {
+		t.Errorf("DeleteVertices(%v) = %v, want %v", ids, dag.String(), expected.String())
+	}
+	if err := dag.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	// caches are up-to-date
+	descendants, _ := dag.GetDescendants("1")
+	if got, want := vertexIDs(descendants), []string{"3", "6"}; !equal(got, want) {
+		t.Errorf("GetDescendants(1) = %v, want %v", got, want)
+	}
+	ancestors, _ := dag.GetAncestors("7")
+	if len(ancestors) != 0 {
+		t.Errorf("GetAncestors(7) = %v, want []", vertexIDs(ancestors))
+	}
+}
+
+func TestDAG_Prune(t *testing.T) {
+
+	//	1 --> 2 --> 3    4 --> 5
+	//	      ^
+	//	      |
+	//	      6
+	dag := NewDAG()
+	for _, id := range []string{"1", "2", "3", "4", "5", "6"} {
+		_ = dag.AddVertexByID(id, "v"+id)
+	}
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("2", "3")
+	_ = dag.AddEdge("4", "5")
+	_ = dag.AddEdge("6", "2")
+
+	// unknown ids prune nothing
+	_, errUnknown := dag.Prune([]string{"1", "foo"})
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("Prune([1, foo]) expected IDUnknownError, got %T", errUnknown)
+	}
+	if order := dag.GetOrder(); order != 6 {
+		t.Errorf("GetOrder() = %d, want 6", order)
+	}
+
+	removed, err := dag.Prune([]string{"1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"4", "5", "6"}; !equal(removed, expected) {
+		t.Errorf("Prune([1]) = %v, want %v", removed, expected)
+	}
+	if ids := dag.SortedVertexIDs(); !equal(ids, []string{"1", "2", "3"}) {
+		t.Errorf("SortedVertexIDs() = %v, want [1 2 3]", ids)
+	}
+	if size := dag.GetSize(); size != 2 {
+		t.Errorf("GetSize() = %d, want 2", size)
+	}
+	if parents, _ := dag.GetParents("2"); len(parents) != 1 {
+		t.Errorf("GetParents(2) = %v, want [1]", vertexIDs(parents))
+	}
+	if err := dag.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	// nothing left to prune
+	removed, _ = dag.Prune([]string{"1"})
+	if len(removed) != 0 {
+		t.Errorf("Prune([1]) = %v, want []", removed)
+	}
+
+	// no roots prune everything
+	removed, _ = dag.Prune(nil)
+	if expected := []string{"1", "2", "3"}; !equal(removed, expected) {
+		t.Errorf("Prune(nil) = %v, want %v", removed, expected)
+	}
+}
+
 func TestDAG_AddEdge(t *testing.T) {
 	dag := NewDAG()
 	v0, _ := dag.AddVertex("0")
@@ -381,7 +711,7 @@ func TestDAG_AddEdge(t *testing.T) {
 	}
 	errLoopDstSrc := dag.AddEdge(v2, v1)
 	if errLoopDstSrc == nil {
-		t.Errorf("AddEdge(v2, v1) = nil, want %T", EdgeLoopError{v2, v1})
+		t.Errorf("AddEdge(v2, v1) = nil, want %T", EdgeLoopError{src: v2, dst: v1})
 	}
 	if _, ok := errLoopDstSrc.(EdgeLoopError); !ok {
 		t.Errorf("AddEdge(v2, v1) expected EdgeLoopError, got %T", errLoopDstSrc)
@@ -413,116 +743,782 @@ func TestDAG_AddEdge(t *testing.T) {
 	}
 }
 
-func TestDAG_DeleteEdge(t *testing.T) {
+func TestDAG_AddEdges(t *testing.T) {
 	dag := NewDAG()
-	v0, _ := dag.AddVertex(iVertex{0})
 	v1, _ := dag.AddVertex("1")
-	_ = dag.AddEdge(v0, v1)
-	if size := dag.GetSize(); size != 1 {
+	v2, _ := dag.AddVertex("2")
+	v3, _ := dag.AddVertex("3")
+	v4, _ := dag.AddVertex("4")
+	_ = dag.AddEdge(v1, v2)
+
+	if err := dag.AddEdges([][2]string{{v2, v3}, {v3, v4}}); err != nil {
+		t.Fatal(err)
+	}
+	if size := dag.GetSize(); size != 3 {
+		t.Errorf("GetSize() = %d, want 3", size)
+	}
+
+	// individually fine, but collectively cyclic
+	dag2 := NewDAG()
+	_ = dag2.AddVertexByID("1", "1")
+	_ = dag2.AddVertexByID("2", "2")
+	_ = dag2.AddVertexByID("3", "3")
+	_ = dag2.AddEdge("1", "2")
+	errLoop := dag2.AddEdges([][2]string{{"2", "3"}, {"3", "1"}})
+	if _, ok := errLoop.(EdgeLoopError); !ok {
+		t.Errorf("AddEdges() expected EdgeLoopError, got %T", errLoop)
+	}
+	if size := dag2.GetSize(); size != 1 {
 		t.Errorf("GetSize() = %d, want 1", size)
 	}
-	_ = dag.DeleteEdge(v0, v1)
-	if size := dag.GetSize(); size != 0 {
-		t.Errorf("GetSize() = %d, want 0", size)
+	if isEdge, _ := dag2.IsEdge("2", "3"); isEdge {
+		t.Errorf("IsEdge(2, 3) = true, want false")
+	}
+	if descendants, _ := dag2.GetDescendants("1"); len(descendants) != 1 {
+		t.Errorf("GetDescendants(1) = %d, want 1", len(descendants))
+	}
+
+	// duplicate within the batch
+	errDuplicate := dag2.AddEdges([][2]string{{"2", "3"}, {"2", "3"}})
+	if _, ok := errDuplicate.(EdgeDuplicateError); !ok {
+		t.Errorf("AddEdges() expected EdgeDuplicateError, got %T", errDuplicate)
+	}
+	if size := dag2.GetSize(); size != 1 {
+		t.Errorf("GetSize() = %d, want 1", size)
 	}
 
 	// unknown
-	errUnknown := dag.DeleteEdge(v0, v1)
-	if errUnknown == nil {
-		t.Errorf("DeleteEdge(v0, v1) = nil, want %T", EdgeUnknownError{})
+	errUnknown := dag2.AddEdges([][2]string{{"2", "3"}, {"3", "foo"}})
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("AddEdges() expected IDUnknownError, got %T", errUnknown)
 	}
-	if _, ok := errUnknown.(EdgeUnknownError); !ok {
-		t.Errorf("DeleteEdge(v0, v1) expected EdgeUnknownError, got %T", errUnknown)
+	if size := dag2.GetSize(); size != 1 {
+		t.Errorf("GetSize() = %d, want 1", size)
 	}
+}
 
-	// nil
-	errNilSrc := dag.DeleteEdge("", v1)
-	if errNilSrc == nil {
-		t.Errorf("DeleteEdge(\"\", v1) = nil, want %T", IDEmptyError{})
+func TestDAG_AddEdgeAndVertices(t *testing.T) {
+	dag := NewDAG()
+
+	// build a graph purely from values
+	edges := [][2]iVertex{{{1}, {2}}, {{1}, {3}}, {{2}, {4}}, {{3}, {4}}}
+	for _, e := range edges {
+		srcID, dstID, err := dag.AddEdgeAndVertices(e[0], e[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if srcID != e[0].ID() || dstID != e[1].ID() {
+			t.Errorf("AddEdgeAndVertices(%v, %v) = %s, %s, want %s, %s", e[0], e[1], srcID, dstID, e[0].ID(), e[1].ID())
+		}
 	}
-	if _, ok := errNilSrc.(IDEmptyError); !ok {
-		t.Errorf("DeleteEdge(\"\", v1) expected IDEmptyError, got %T", errNilSrc)
+	if order := dag.GetOrder(); order != 4 {
+		t.Errorf("GetOrder() = %d, want 4", order)
 	}
-	errNilDst := dag.DeleteEdge(v0, "")
-	if errNilDst == nil {
-		t.Errorf("DeleteEdge(v0, \"\") = nil, want %T", IDEmptyError{})
+	if size := dag.GetSize(); size != 4 {
+		t.Errorf("GetSize() = %d, want 4", size)
 	}
-	if _, ok := errNilDst.(IDEmptyError); !ok {
-		t.Errorf("DeleteEdge(v0, \"\") expected IDEmptyError, got %T", errNilDst)
+
+	// without IDInterface, new ids are generated
+	srcID, dstID, err := dag.AddEdgeAndVertices("foo", iVertex{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if srcID == "" || dstID != "1" {
+		t.Errorf("AddEdgeAndVertices(foo, 1) = %s, %s, want <uuid>, 1", srcID, dstID)
 	}
 
-	// unknown
-	errUnknownSrc := dag.DeleteEdge("foo", v1)
-	if errUnknownSrc == nil {
-		t.Errorf("DeleteEdge(foo, v1) = nil, want %T", IDUnknownError{})
+	// duplicate
+	_, _, errDuplicate := dag.AddEdgeAndVertices(iVertex{1}, iVertex{2})
+	if _, ok := errDuplicate.(EdgeDuplicateError); !ok {
+		t.Errorf("AddEdgeAndVertices(1, 2) expected EdgeDuplicateError, got %T", errDuplicate)
 	}
-	if _, ok := errUnknownSrc.(IDUnknownError); !ok {
-		t.Errorf("DeleteEdge(foo, v1) expected IDUnknownError, got %T", errUnknownSrc)
+
+	// loop
+	_, _, errLoop := dag.AddEdgeAndVertices(iVertex{4}, iVertex{1})
+	if _, ok := errLoop.(EdgeLoopError); !ok {
+		t.Errorf("AddEdgeAndVertices(4, 1) expected EdgeLoopError, got %T", errLoop)
 	}
-	errUnknownDst := dag.DeleteEdge(v0, "foo")
-	if errUnknownDst == nil {
-		t.Errorf("DeleteEdge(v0, \"foo\") = nil, want %T", IDUnknownError{})
+
+	// nil doesn't add the other vertex
+	_, _, errNil := dag.AddEdgeAndVertices(iVertex{5}, nil)
+	if _, ok := errNil.(VertexNilError); !ok {
+		t.Errorf("AddEdgeAndVertices(5, nil) expected VertexNilError, got %T", errNil)
 	}
-	if _, ok := errUnknownDst.(IDUnknownError); !ok {
-		t.Errorf("DeleteEdge(v0, \"foo\") expected IDUnknownError, got %T", errUnknownDst)
+	if order := dag.GetOrder(); order != 5 {
+		t.Errorf("GetOrder() = %d, want 5", order)
 	}
 }
 
-func TestDAG_IsLeaf(t *testing.T) {
+func TestDAG_AddEdgeLoopPath(t *testing.T) {
 	dag := NewDAG()
-	v1, _ := dag.AddVertex("1")
-	v2, _ := dag.AddVertex("2")
-	v3, _ := dag.AddVertex("3")
+	for i := 1; i <= 5; i++ {
+		_ = dag.AddVertexByID(strconv.Itoa(i), i)
+	}
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("2", "3")
+	_ = dag.AddEdge("3", "4")
+	_ = dag.AddEdge("1", "5")
+	_ = dag.AddEdge("5", "4")
 
-	_ = dag.AddEdge(v1, v2)
-	_ = dag.AddEdge(v1, v3)
-	if isLeaf, _ := dag.IsLeaf(v1); isLeaf {
-		t.Errorf("IsLeaf(v1) = true, want false")
+	err := dag.AddEdge("4", "1")
+	var errLoop EdgeLoopError
+	if !errors.As(err, &errLoop) {
+		t.Fatalf("AddEdge(4, 1) expected EdgeLoopError, got %T", err)
 	}
-	if isLeaf, _ := dag.IsLeaf(v2); !isLeaf {
-		t.Errorf("IsLeaf(v2) = false, want true")
+
+	// the path must be a valid route from dst to src
+	path := errLoop.Path
+	if len(path) < 2 || path[0] != "1" || path[len(path)-1] != "4" {
+		t.Fatalf("EdgeLoopError.Path = %v, want a path from 1 to 4", path)
 	}
-	if isLeaf, _ := dag.IsLeaf(v3); !isLeaf {
-		t.Errorf("IsLeaf(v3) = false, want true")
+	for i := 1; i < len(path); i++ {
+		if isEdge, _ := dag.IsEdge(path[i-1], path[i]); !isEdge {
+			t.Errorf("EdgeLoopError.Path = %v, but there is no edge from %s to %s", path, path[i-1], path[i])
+		}
 	}
-	if _, err := dag.IsLeaf("foo"); err == nil {
-		t.Errorf("IsLeaf(foo) = nil, want %T", IDUnknownError{})
+	expected := []string{"1", "5", "4"}
+	if !equal(path, expected) {
+		t.Errorf("EdgeLoopError.Path = %v, want %v", path, expected)
 	}
-	if _, err := dag.IsLeaf(""); err == nil {
-		t.Errorf("IsLeaf(\"\") = nil, want %T", IDEmptyError{})
+	if !errors.Is(err, ErrEdgeLoop) {
+		t.Errorf("errors.Is(AddEdge(4, 1), ErrEdgeLoop) = false, want true")
 	}
 }
 
-func TestDAG_IsRoot(t *testing.T) {
+func TestDAG_WouldCreateCycle(t *testing.T) {
 	dag := NewDAG()
-	v1, _ := dag.AddVertex("1")
-	v2, _ := dag.AddVertex("2")
-	v3, _ := dag.AddVertex("3")
-
-	_ = dag.AddEdge(v1, v2)
-	_ = dag.AddEdge(v1, v3)
-	if isRoot, _ := dag.IsRoot(v1); !isRoot {
-		t.Errorf("IsRoot(v1) = false, want true")
+	for i := 1; i <= 4; i++ {
+		_ = dag.AddVertexByID(strconv.Itoa(i), i)
 	}
-	if isRoot, _ := dag.IsRoot(v2); isRoot {
-		t.Errorf("IsRoot(v2) = true, want false")
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("2", "3")
+
+	cases := []struct {
+		src, dst string
+		expected bool
+	}{
+		{"3", "1", true},
+		{"2", "1", true},
+		{"3", "2", true},
+		{"2", "2", true},
+		{"1", "3", false},
+		{"1", "2", false},
+		{"4", "1", false},
+		{"3", "4", false},
+	}
+	for _, c := range cases {
+		cycle, err := dag.WouldCreateCycle(c.src, c.dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cycle != c.expected {
+			t.Errorf("WouldCreateCycle(%s, %s) = %v, want %v", c.src, c.dst, cycle, c.expected)
+		}
 	}
-	if isRoot, _ := dag.IsRoot(v3); isRoot {
-		t.Errorf("IsRoot(v3) = true, want false")
+	if size := dag.GetSize(); size != 2 {
+		t.Errorf("GetSize() = %d, want 2", size)
 	}
-	if _, err := dag.IsRoot("foo"); err == nil {
-		t.Errorf("IsRoot(foo) = nil, want %T", IDUnknownError{})
+
+	// nil
+	_, errNil := dag.WouldCreateCycle("", "1")
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("WouldCreateCycle(\"\", \"1\") expected IDEmptyError, got %T", errNil)
 	}
-	if _, err := dag.IsRoot(""); err == nil {
-		t.Errorf("IsRoot(\"\") = nil, want %T", IDEmptyError{})
+
+	// unknown
+	_, errUnknown := dag.WouldCreateCycle("1", "foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("WouldCreateCycle(\"1\", \"foo\") expected IDUnknownError, got %T", errUnknown)
 	}
 }
 
-func TestDAG_GetChildren(t *testing.T) {
+func TestDAG_ReplaceEdge(t *testing.T) {
 	dag := NewDAG()
-	v1, _ := dag.AddVertex("1")
-	v2, _ := dag.AddVertex("2")
-	v3, _ := dag.AddVertex("3")
+	for i := 1; i <= 4; i++ {
+		_ = dag.AddVertexByID(strconv.Itoa(i), i)
+	}
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("2", "3")
+
+	// populate the caches
+	_, _ = dag.GetDescendants("1")
+	_, _ = dag.GetAncestors("3")
+
+	if err := dag.ReplaceEdge("2", "3", "4"); err != nil {
+		t.Fatal(err)
+	}
+	if isEdge, _ := dag.IsEdge("2", "3"); isEdge {
+		t.Errorf("IsEdge(2, 3) = true, want false")
+	}
+	if isEdge, _ := dag.IsEdge("2", "4"); !isEdge {
+		t.Errorf("IsEdge(2, 4) = false, want true")
+	}
+	if size := dag.GetSize(); size != 2 {
+		t.Errorf("GetSize() = %d, want 2", size)
+	}
+	if descendants, _ := dag.GetDescendants("1"); len(descendants) != 2 {
+		t.Errorf("GetDescendants(1) = %v, want 2 descendants", descendants)
+	}
+	if ancestors, _ := dag.GetAncestors("3"); len(ancestors) != 0 {
+		t.Errorf("GetAncestors(3) = %v, want none", ancestors)
+	}
+	if err := dag.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	// loop (the old edge is kept)
+	_ = dag.AddEdge("4", "3")
+	_ = dag.SetEdgeLabel("4", "3", "l43")
+	errLoop := dag.ReplaceEdge("4", "3", "1")
+	if _, ok := errLoop.(EdgeLoopError); !ok {
+		t.Errorf("ReplaceEdge(4, 3, 1) expected EdgeLoopError, got %T", errLoop)
+	}
+	if label, _ := dag.GetEdgeLabel("4", "3"); label != "l43" {
+		t.Errorf("GetEdgeLabel(4, 3) = %v, want l43", label)
+	}
+
+	// duplicate
+	_ = dag.AddEdge("1", "3")
+	errDuplicate := dag.ReplaceEdge("1", "2", "3")
+	if _, ok := errDuplicate.(EdgeDuplicateError); !ok {
+		t.Errorf("ReplaceEdge(1, 2, 3) expected EdgeDuplicateError, got %T", errDuplicate)
+	}
+
+	// unknown edge
+	errUnknown := dag.ReplaceEdge("3", "1", "2")
+	if _, ok := errUnknown.(EdgeUnknownError); !ok {
+		t.Errorf("ReplaceEdge(3, 1, 2) expected EdgeUnknownError, got %T", errUnknown)
+	}
+
+	// src equals dst
+	errEqual := dag.ReplaceEdge("1", "2", "1")
+	if _, ok := errEqual.(SrcDstEqualError); !ok {
+		t.Errorf("ReplaceEdge(1, 2, 1) expected SrcDstEqualError, got %T", errEqual)
+	}
+	if size := dag.GetSize(); size != 4 {
+		t.Errorf("GetSize() = %d, want 4", size)
+	}
+}
+
+func TestDAG_ReParent(t *testing.T) {
+
+	// 1 --> 3 --> 4
+	//       ^
+	// 2 ----+     5
+	dag := NewDAG()
+	for i := 1; i <= 5; i++ {
+		_ = dag.AddVertexByID(strconv.Itoa(i), i)
+	}
+	_ = dag.AddEdge("1", "3")
+	_ = dag.AddWeightedEdge("2", "3", 2)
+	_ = dag.AddEdge("3", "4")
+
+	// populate the caches
+	_, _ = dag.GetDescendants("1")
+	_, _ = dag.GetAncestors("4")
+
+	if err := dag.ReParent("3", "5"); err != nil {
+		t.Fatal(err)
+	}
+	if parents, _ := dag.GetParents("3"); len(parents) != 1 || parents["5"] == nil {
+		t.Errorf("GetParents(3) = %v, want only 5", parents)
+	}
+	if descendants, _ := dag.GetDescendants("1"); len(descendants) != 0 {
+		t.Errorf("GetDescendants(1) = %v, want none", descendants)
+	}
+	if ancestors, _ := dag.GetAncestors("4"); len(ancestors) != 2 {
+		t.Errorf("GetAncestors(4) = %v, want 3 and 5", ancestors)
+	}
+	if size := dag.GetSize(); size != 2 {
+		t.Errorf("GetSize() = %d, want 2", size)
+	}
+
+	// loop (the old edges are restored, i.e. kept)
+	_ = dag.AddEdge("1", "3")
+	_ = dag.SetEdgeLabel("5", "3", "l53")
+	errLoop := dag.ReParent("3", "4")
+	if _, ok := errLoop.(EdgeLoopError); !ok {
+		t.Errorf("ReParent(3, 4) expected EdgeLoopError, got %T", errLoop)
+	}
+	if parents, _ := dag.GetParents("3"); len(parents) != 2 {
+		t.Errorf("GetParents(3) = %v, want 1 and 5", parents)
+	}
+	if label, _ := dag.GetEdgeLabel("5", "3"); label != "l53" {
+		t.Errorf("GetEdgeLabel(5, 3) = %v, want l53", label)
+	}
+	if isEdge, _ := dag.IsEdge("4", "3"); isEdge {
+		t.Errorf("IsEdge(4, 3) = true, want false")
+	}
+	if size := dag.GetSize(); size != 3 {
+		t.Errorf("GetSize() = %d, want 3", size)
+	}
+
+	// an existing parent keeps its edge (and its attributes)
+	if err := dag.ReParent("3", "5"); err != nil {
+		t.Fatal(err)
+	}
+	if parents, _ := dag.GetParents("3"); len(parents) != 1 {
+		t.Errorf("GetParents(3) = %v, want only 5", parents)
+	}
+	if label, _ := dag.GetEdgeLabel("5", "3"); label != "l53" {
+		t.Errorf("GetEdgeLabel(5, 3) = %v, want l53", label)
+	}
+
+	// child equals new parent and unknown
+	errEqual := dag.ReParent("3", "3")
+	if _, ok := errEqual.(SrcDstEqualError); !ok {
+		t.Errorf("ReParent(3, 3) expected SrcDstEqualError, got %T", errEqual)
+	}
+	errUnknown := dag.ReParent("3", "foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("ReParent(3, foo) expected IDUnknownError, got %T", errUnknown)
+	}
+	if err := dag.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDAG_DeleteEdge(t *testing.T) {
+	dag := NewDAG()
+	v0, _ := dag.AddVertex(iVertex{0})
+	v1, _ := dag.AddVertex("1")
+	_ = dag.AddEdge(v0, v1)
+	if size := dag.GetSize(); size != 1 {
+		t.Errorf("GetSize() = %d, want 1", size)
+	}
+	_ = dag.DeleteEdge(v0, v1)
+	if size := dag.GetSize(); size != 0 {
+		t.Errorf("GetSize() = %d, want 0", size)
+	}
+
+	// unknown
+	errUnknown := dag.DeleteEdge(v0, v1)
+	if errUnknown == nil {
+		t.Errorf("DeleteEdge(v0, v1) = nil, want %T", EdgeUnknownError{})
+	}
+	if _, ok := errUnknown.(EdgeUnknownError); !ok {
+		t.Errorf("DeleteEdge(v0, v1) expected EdgeUnknownError, got %T", errUnknown)
+	}
+
+	// nil
+	errNilSrc := dag.DeleteEdge("", v1)
+	if errNilSrc == nil {
+		t.Errorf("DeleteEdge(\"\", v1) = nil, want %T", IDEmptyError{})
+	}
+	if _, ok := errNilSrc.(IDEmptyError); !ok {
+		t.Errorf("DeleteEdge(\"\", v1) expected IDEmptyError, got %T", errNilSrc)
+	}
+	errNilDst := dag.DeleteEdge(v0, "")
+	if errNilDst == nil {
+		t.Errorf("DeleteEdge(v0, \"\") = nil, want %T", IDEmptyError{})
+	}
+	if _, ok := errNilDst.(IDEmptyError); !ok {
+		t.Errorf("DeleteEdge(v0, \"\") expected IDEmptyError, got %T", errNilDst)
+	}
+
+	// unknown
+	errUnknownSrc := dag.DeleteEdge("foo", v1)
+	if errUnknownSrc == nil {
+		t.Errorf("DeleteEdge(foo, v1) = nil, want %T", IDUnknownError{})
+	}
+	if _, ok := errUnknownSrc.(IDUnknownError); !ok {
+		t.Errorf("DeleteEdge(foo, v1) expected IDUnknownError, got %T", errUnknownSrc)
+	}
+	errUnknownDst := dag.DeleteEdge(v0, "foo")
+	if errUnknownDst == nil {
+		t.Errorf("DeleteEdge(v0, \"foo\") = nil, want %T", IDUnknownError{})
+	}
+	if _, ok := errUnknownDst.(IDUnknownError); !ok {
+		t.Errorf("DeleteEdge(v0, \"foo\") expected IDUnknownError, got %T", errUnknownDst)
+	}
+}
+
+func TestDAG_GetSizeInvariant(t *testing.T) {
+	dag := NewDAG()
+	rnd := rand.New(rand.NewSource(1))
+	ids := make([]string, 20)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i)
+		_ = dag.AddVertexByID(ids[i], i)
+	}
+
+	recomputed := func() int {
+		count := 0
+		for _, value := range dag.outboundEdge {
+			count += len(value)
+		}
+		return count
+	}
+
+	for i := 0; i < 2000; i++ {
+		src := ids[rnd.Intn(len(ids))]
+		dst := ids[rnd.Intn(len(ids))]
+		switch rnd.Intn(10) {
+		case 0:
+			_ = dag.DeleteVertex(src)
+		case 1:
+			_ = dag.AddVertexByID(src, src)
+		case 2, 3, 4:
+			_ = dag.DeleteEdge(src, dst)
+		case 5:
+			dag.ReduceTransitively()
+		default:
+			_ = dag.AddEdge(src, dst)
+		}
+		if size, want := dag.GetSize(), recomputed(); size != want {
+			t.Fatalf("GetSize() = %d, want %d (after %d operations)", size, want, i+1)
+		}
+	}
+}
+
+func TestDAG_ContractEdge(t *testing.T) {
+	dag := getTestPathDAG()
+
+	// 2 inherits the children of 4 (5 and 6)
+	if err := dag.ContractEdge("2", "4"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dag.GetVertex("4"); err == nil {
+		t.Errorf("GetVertex(4) = nil, want IDUnknownError")
+	}
+	if v, _ := dag.GetVertex("2"); v != "v2" {
+		t.Errorf("GetVertex(2) = %v, want v2", v)
+	}
+	children, _ := dag.GetChildren("2")
+	if got, want := vertexIDs(children), []string{"5", "6"}; !equal(got, want) {
+		t.Errorf("GetChildren(2) = %v, want %v", got, want)
+	}
+
+	// 1 already is a parent of 6, thus, the edge 1 -> 6 isn't duplicated
+	if err := dag.ContractEdge("1", "2"); err != nil {
+		t.Fatal(err)
+	}
+	children, _ = dag.GetChildren("1")
+	if got, want := vertexIDs(children), []string{"3", "5", "6"}; !equal(got, want) {
+		t.Errorf("GetChildren(1) = %v, want %v", got, want)
+	}
+	if size := dag.GetSize(); size != 5 {
+		t.Errorf("GetSize() = %d, want 5", size)
+	}
+	if err := dag.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	// unknown edge
+	errUnknownEdge := dag.ContractEdge("1", "7")
+	if _, ok := errUnknownEdge.(EdgeUnknownError); !ok {
+		t.Errorf("ContractEdge(1, 7) expected EdgeUnknownError, got %T", errUnknownEdge)
+	}
+
+	// nil
+	errNil := dag.ContractEdge("", "7")
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("ContractEdge(\"\", 7) expected IDEmptyError, got %T", errNil)
+	}
+}
+
+func TestDAG_ContractEdgeLoop(t *testing.T) {
+
+	//	1 --> 2 --> 3
+	//	|           |
+	//	+---> 4 <---+
+	dag := NewDAG()
+	for _, id := range []string{"1", "2", "3", "4"} {
+		_ = dag.AddVertexByID(id, "v"+id)
+	}
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("2", "3")
+	_ = dag.AddEdge("3", "4")
+	_ = dag.AddEdge("1", "4")
+
+	// rewiring the parent 3 of 4 to 1 would create the loop 1 -> 2 -> 3 -> 1
+	// and thus is skipped
+	if err := dag.ContractEdge("1", "4"); err != nil {
+		t.Fatal(err)
+	}
+	parents, _ := dag.GetParents("1")
+	if len(parents) != 0 {
+		t.Errorf("GetParents(1) = %v, want []", vertexIDs(parents))
+	}
+	if leaves := vertexIDs(dag.GetLeaves()); !equal(leaves, []string{"3"}) {
+		t.Errorf("GetLeaves() = %v, want [3]", leaves)
+	}
+	if err := dag.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDAG_ContractEdgeParents(t *testing.T) {
+
+	//	3 --> 2 --> 5
+	//	      ^
+	//	1 ----+
+	//	|     |
+	//	+---> 4
+	dag := NewDAG()
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		_ = dag.AddVertexByID(id, "v"+id)
+	}
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("3", "2")
+	_ = dag.AddEdge("4", "2")
+	_ = dag.AddEdge("1", "4")
+	_ = dag.AddEdge("2", "5")
+
+	// the parent 3 of 2 is moved onto 1, while the parent 4 of 2 is skipped (as
+	// 4 is a descendant of 1)
+	if err := dag.ContractEdge("1", "2"); err != nil {
+		t.Fatal(err)
+	}
+	parents, _ := dag.GetParents("1")
+	if got, want := vertexIDs(parents), []string{"3"}; !equal(got, want) {
+		t.Errorf("GetParents(1) = %v, want %v", got, want)
+	}
+	children, _ := dag.GetChildren("1")
+	if got, want := vertexIDs(children), []string{"4", "5"}; !equal(got, want) {
+		t.Errorf("GetChildren(1) = %v, want %v", got, want)
+	}
+	if descendants, _ := dag.GetDescendants("3"); len(descendants) != 3 {
+		t.Errorf("len(GetDescendants(3)) = %d, want 3", len(descendants))
+	}
+	if size := dag.GetSize(); size != 3 {
+		t.Errorf("GetSize() = %d, want 3", size)
+	}
+	if err := dag.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	// a failing contraction leaves the graph unchanged
+	dag.Freeze()
+	if err := dag.ContractEdge("1", "4"); !errors.Is(err, ErrFrozen) {
+		t.Errorf("ContractEdge(1, 4) = %v, want %v", err, ErrFrozen)
+	}
+	if order, size := dag.GetOrder(), dag.GetSize(); order != 4 || size != 3 {
+		t.Errorf("GetOrder(), GetSize() = %d, %d, want 4, 3", order, size)
+	}
+}
+
+func TestDAG_Stats(t *testing.T) {
+	order, size, roots, leaves := NewDAG().Stats()
+	if order != 0 || size != 0 || roots != 0 || leaves != 0 {
+		t.Errorf("Stats() = %d, %d, %d, %d, want 0, 0, 0, 0", order, size, roots, leaves)
+	}
+
+	order, size, roots, leaves = getTestPathDAG().Stats()
+	if order != 7 || size != 8 || roots != 1 || leaves != 2 {
+		t.Errorf("Stats() = %d, %d, %d, %d, want 7, 8, 1, 2", order, size, roots, leaves)
+	}
+}
+
+func TestDAG_StatsConcurrentMutation(t *testing.T) {
+	dag := NewDAG()
+	ids := make([]string, 10)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i)
+	}
+
+	// churn
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for g := 0; g < 3; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(seed))
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				src := ids[rnd.Intn(len(ids))]
+				dst := ids[rnd.Intn(len(ids))]
+				switch rnd.Intn(4) {
+				case 0:
+					_ = dag.AddVertexByID(src, src)
+				case 1:
+					_ = dag.DeleteVertex(src)
+				case 2:
+					_ = dag.DeleteEdge(src, dst)
+				default:
+					_ = dag.AddEdge(src, dst)
+				}
+			}
+		}(int64(g))
+	}
+
+	for i := 0; i < 2000; i++ {
+		order, size, roots, leaves := dag.Stats()
+		if size > order*(order-1)/2 {
+			t.Errorf("Stats() = %d vertices and %d edges", order, size)
+		}
+		if roots > order || leaves > order || (order > 0 && (roots == 0 || leaves == 0)) {
+			t.Errorf("Stats() = %d vertices, %d roots, and %d leaves", order, roots, leaves)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestDAG_IsLeaf(t *testing.T) {
+	dag := NewDAG()
+	v1, _ := dag.AddVertex("1")
+	v2, _ := dag.AddVertex("2")
+	v3, _ := dag.AddVertex("3")
+
+	_ = dag.AddEdge(v1, v2)
+	_ = dag.AddEdge(v1, v3)
+	if isLeaf, _ := dag.IsLeaf(v1); isLeaf {
+		t.Errorf("IsLeaf(v1) = true, want false")
+	}
+	if isLeaf, _ := dag.IsLeaf(v2); !isLeaf {
+		t.Errorf("IsLeaf(v2) = false, want true")
+	}
+	if isLeaf, _ := dag.IsLeaf(v3); !isLeaf {
+		t.Errorf("IsLeaf(v3) = false, want true")
+	}
+	if _, err := dag.IsLeaf("foo"); err == nil {
+		t.Errorf("IsLeaf(foo) = nil, want %T", IDUnknownError{})
+	}
+	if _, err := dag.IsLeaf(""); err == nil {
+		t.Errorf("IsLeaf(\"\") = nil, want %T", IDEmptyError{})
+	}
+}
+
+func TestDAG_IsRoot(t *testing.T) {
+	dag := NewDAG()
+	v1, _ := dag.AddVertex("1")
+	v2, _ := dag.AddVertex("2")
+	v3, _ := dag.AddVertex("3")
+
+	_ = dag.AddEdge(v1, v2)
+	_ = dag.AddEdge(v1, v3)
+	if isRoot, _ := dag.IsRoot(v1); !isRoot {
+		t.Errorf("IsRoot(v1) = false, want true")
+	}
+	if isRoot, _ := dag.IsRoot(v2); isRoot {
+		t.Errorf("IsRoot(v2) = true, want false")
+	}
+	if isRoot, _ := dag.IsRoot(v3); isRoot {
+		t.Errorf("IsRoot(v3) = true, want false")
+	}
+	if _, err := dag.IsRoot("foo"); err == nil {
+		t.Errorf("IsRoot(foo) = nil, want %T", IDUnknownError{})
+	}
+	if _, err := dag.IsRoot(""); err == nil {
+		t.Errorf("IsRoot(\"\") = nil, want %T", IDEmptyError{})
+	}
+}
+
+func TestDAG_GetLeavesOf(t *testing.T) {
+	dag := getTestPathDAG()
+
+	cases := []struct {
+		id       string
+		expected []string
+	}{
+		{"1", []string{"6", "7"}},
+		{"2", []string{"6", "7"}},
+		{"3", []string{"7"}},
+		{"7", []string{}},
+	}
+	for _, c := range cases {
+		leaves, err := dag.GetLeavesOf(c.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ids := vertexIDs(leaves); !equal(ids, c.expected) {
+			t.Errorf("GetLeavesOf(%s) = %v, want %v", c.id, ids, c.expected)
+		}
+	}
+
+	// nil
+	_, errNil := dag.GetLeavesOf("")
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("GetLeavesOf(\"\") expected IDEmptyError, got %T", errNil)
+	}
+
+	// unknown
+	_, errUnknown := dag.GetLeavesOf("foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("GetLeavesOf(\"foo\") expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_GetRootsOf(t *testing.T) {
+	dag := getTestWalkDAG2()
+
+	cases := []struct {
+		id       string
+		expected []string
+	}{
+		{"5", []string{"1", "2", "4"}},
+		{"3", []string{"1", "2"}},
+		{"1", []string{}},
+	}
+	for _, c := range cases {
+		roots, err := dag.GetRootsOf(c.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ids := vertexIDs(roots); !equal(ids, c.expected) {
+			t.Errorf("GetRootsOf(%s) = %v, want %v", c.id, ids, c.expected)
+		}
+	}
+
+	// nil
+	_, errNil := dag.GetRootsOf("")
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("GetRootsOf(\"\") expected IDEmptyError, got %T", errNil)
+	}
+
+	// unknown
+	_, errUnknown := dag.GetRootsOf("foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("GetRootsOf(\"foo\") expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_GetIncidentEdges(t *testing.T) {
+	dag := NewDAG()
+	for _, id := range []string{"1", "2", "3", "4"} {
+		_ = dag.AddVertexByID(id, "v"+id)
+	}
+	_ = dag.AddEdge("1", "3")
+	_ = dag.AddEdge("2", "3")
+	_ = dag.AddEdge("3", "4")
+
+	inbound, outbound, err := dag.GetIncidentEdges("3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantInbound := [][2]string{{"1", "3"}, {"2", "3"}}
+	if deep.Equal(inbound, wantInbound) != nil {
+		t.Errorf("GetIncidentEdges() inbound = %v, want %v", inbound, wantInbound)
+	}
+	wantOutbound := [][2]string{{"3", "4"}}
+	if deep.Equal(outbound, wantOutbound) != nil {
+		t.Errorf("GetIncidentEdges() outbound = %v, want %v", outbound, wantOutbound)
+	}
+
+	// root
+	inbound, outbound, _ = dag.GetIncidentEdges("1")
+	if len(inbound) != 0 || len(outbound) != 1 {
+		t.Errorf("GetIncidentEdges(1) = %v, %v, want [], [[1 3]]", inbound, outbound)
+	}
+
+	// unknown
+	_, _, errUnknown := dag.GetIncidentEdges("foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("GetIncidentEdges(foo) expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_GetChildren(t *testing.T) {
+	dag := NewDAG()
+	v1, _ := dag.AddVertex("1")
+	v2, _ := dag.AddVertex("2")
+	v3, _ := dag.AddVertex("3")
 
 	_ = dag.AddEdge(v1, v2)
 	_ = dag.AddEdge(v1, v3)
@@ -563,37 +1559,132 @@ func TestDAG_GetParents(t *testing.T) {
 	v2, _ := dag.addVertex("2")
 	v3, _ := dag.addVertex("3")
 	_ = dag.AddEdge(v1, v3)
-	_ = dag.AddEdge(v2, v3)
+	_ = dag.AddEdge(v2, v3)
+
+	parents, _ := dag.GetParents(v3)
+	if length := len(parents); length != 2 {
+		t.Errorf("GetParents(v3) = %d, want 2", length)
+	}
+	if _, exists := parents[v1]; !exists {
+		t.Errorf("GetParents(v3)[v1] = %t, want true", exists)
+	}
+	if _, exists := parents[v2]; !exists {
+		t.Errorf("GetParents(v3)[v2] = %t, want true", exists)
+	}
+
+	// nil
+	_, errNil := dag.GetParents("")
+	if errNil == nil {
+		t.Errorf("GetParents(\"\") = nil, want %T", IDEmptyError{})
+	}
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("GetParents(\"\") expected IDEmptyError, got %T", errNil)
+	}
+
+	// unknown
+	_, errUnknown := dag.GetParents("foo")
+	if errUnknown == nil {
+		t.Errorf("GetParents(\"foo\") = nil, want %T", IDUnknownError{"foo"})
+	}
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("GetParents(\"foo\") expected IDUnknownError, got %T", errUnknown)
+	}
+
+}
+
+func TestDAG_GetParentCount(t *testing.T) {
+	dag := NewDAG()
+	v1, _ := dag.AddVertex("1")
+	v2, _ := dag.AddVertex("2")
+	v3, _ := dag.AddVertex("3")
+	_ = dag.AddEdge(v1, v3)
+	_ = dag.AddEdge(v2, v3)
+
+	if count, _ := dag.GetParentCount(v3); count != 2 {
+		t.Errorf("GetParentCount(v3) = %d, want 2", count)
+	}
+	if count, _ := dag.GetParentCount(v1); count != 0 {
+		t.Errorf("GetParentCount(v1) = %d, want 0", count)
+	}
+
+	// nil
+	_, errNil := dag.GetParentCount("")
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("GetParentCount(\"\") expected IDEmptyError, got %T", errNil)
+	}
+
+	// unknown
+	_, errUnknown := dag.GetParentCount("foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("GetParentCount(\"foo\") expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_GetChildCount(t *testing.T) {
+	dag := NewDAG()
+	v1, _ := dag.AddVertex("1")
+	v2, _ := dag.AddVertex("2")
+	v3, _ := dag.AddVertex("3")
+	_ = dag.AddEdge(v1, v2)
+	_ = dag.AddEdge(v1, v3)
 
-	parents, _ := dag.GetParents(v3)
-	if length := len(parents); length != 2 {
-		t.Errorf("GetParents(v3) = %d, want 2", length)
+	if count, _ := dag.GetChildCount(v1); count != 2 {
+		t.Errorf("GetChildCount(v1) = %d, want 2", count)
 	}
-	if _, exists := parents[v1]; !exists {
-		t.Errorf("GetParents(v3)[v1] = %t, want true", exists)
+	if count, _ := dag.GetChildCount(v3); count != 0 {
+		t.Errorf("GetChildCount(v3) = %d, want 0", count)
 	}
-	if _, exists := parents[v2]; !exists {
-		t.Errorf("GetParents(v3)[v2] = %t, want true", exists)
+	_ = dag.DeleteEdge(v1, v2)
+	if count, _ := dag.GetChildCount(v1); count != 1 {
+		t.Errorf("GetChildCount(v1) = %d, want 1", count)
 	}
 
 	// nil
-	_, errNil := dag.GetParents("")
-	if errNil == nil {
-		t.Errorf("GetParents(\"\") = nil, want %T", IDEmptyError{})
-	}
+	_, errNil := dag.GetChildCount("")
 	if _, ok := errNil.(IDEmptyError); !ok {
-		t.Errorf("GetParents(\"\") expected IDEmptyError, got %T", errNil)
+		t.Errorf("GetChildCount(\"\") expected IDEmptyError, got %T", errNil)
 	}
 
 	// unknown
-	_, errUnknown := dag.GetParents("foo")
-	if errUnknown == nil {
-		t.Errorf("GetParents(\"foo\") = nil, want %T", IDUnknownError{"foo"})
-	}
+	_, errUnknown := dag.GetChildCount("foo")
 	if _, ok := errUnknown.(IDUnknownError); !ok {
-		t.Errorf("GetParents(\"foo\") expected IDUnknownError, got %T", errUnknown)
+		t.Errorf("GetChildCount(\"foo\") expected IDUnknownError, got %T", errUnknown)
 	}
+}
+
+func TestDAG_InDegrees(t *testing.T) {
+	dag := getTestPathDAG()
+	inDegrees := dag.InDegrees()
+	expected := map[string]int{"1": 0, "2": 1, "3": 1, "4": 1, "5": 2, "6": 2, "7": 1}
+	if diff := deep.Equal(inDegrees, expected); diff != nil {
+		t.Errorf("InDegrees() = %v, want %v", inDegrees, expected)
+	}
+	total := 0
+	for _, degree := range inDegrees {
+		total += degree
+	}
+	if total != dag.GetSize() {
+		t.Errorf("sum of InDegrees() = %d, want %d", total, dag.GetSize())
+	}
+	if inDegrees := NewDAG().InDegrees(); len(inDegrees) != 0 {
+		t.Errorf("InDegrees() = %v, want empty map", inDegrees)
+	}
+}
 
+func TestDAG_OutDegrees(t *testing.T) {
+	dag := getTestPathDAG()
+	outDegrees := dag.OutDegrees()
+	expected := map[string]int{"1": 3, "2": 1, "3": 1, "4": 2, "5": 1, "6": 0, "7": 0}
+	if diff := deep.Equal(outDegrees, expected); diff != nil {
+		t.Errorf("OutDegrees() = %v, want %v", outDegrees, expected)
+	}
+	total := 0
+	for _, degree := range outDegrees {
+		total += degree
+	}
+	if total != dag.GetSize() {
+		t.Errorf("sum of OutDegrees() = %d, want %d", total, dag.GetSize())
+	}
 }
 
 func TestDAG_GetDescendants(t *testing.T) {
@@ -639,6 +1730,223 @@ func TestDAG_GetDescendants(t *testing.T) {
 	}
 }
 
+func TestDAG_DescendantAndAncestorCount(t *testing.T) {
+	dag := NewDAG()
+	v1, _ := dag.AddVertex("1")
+	v2, _ := dag.AddVertex("2")
+	v3, _ := dag.AddVertex("3")
+	v4, _ := dag.AddVertex("4")
+
+	_ = dag.AddEdge(v1, v2)
+	_ = dag.AddEdge(v2, v3)
+	_ = dag.AddEdge(v2, v4)
+
+	cases := []struct {
+		id                     string
+		descendants, ancestors int
+	}{
+		{v1, 3, 0},
+		{v2, 2, 1},
+		{v3, 0, 2},
+		{v4, 0, 2},
+	}
+	for _, c := range cases {
+		if count, _ := dag.DescendantCount(c.id); count != c.descendants {
+			t.Errorf("DescendantCount(%s) = %d, want %d", c.id, count, c.descendants)
+		}
+		if count, _ := dag.AncestorCount(c.id); count != c.ancestors {
+			t.Errorf("AncestorCount(%s) = %d, want %d", c.id, count, c.ancestors)
+		}
+		desc, _ := dag.GetDescendants(c.id)
+		if count, _ := dag.DescendantCount(c.id); count != len(desc) {
+			t.Errorf("DescendantCount(%s) = %d, want %d", c.id, count, len(desc))
+		}
+	}
+
+	// nil
+	_, errNil := dag.DescendantCount("")
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("DescendantCount(\"\") expected IDEmptyError, got %T", errNil)
+	}
+
+	// unknown
+	_, errUnknown := dag.AncestorCount("foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("AncestorCount(\"foo\") expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_GetRelativesUntil(t *testing.T) {
+
+	//	1 --> 2 --> barrier --> 4 --> 5
+	//	|                       ^
+	//	+---> 6 ----------------+
+	//	            ^
+	//	7 ----------+
+	dag := NewDAG()
+	for _, id := range []string{"1", "2", "barrier", "4", "5", "6", "7"} {
+		_ = dag.AddVertexByID(id, "v"+id)
+	}
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("2", "barrier")
+	_ = dag.AddEdge("barrier", "4")
+	_ = dag.AddEdge("4", "5")
+	_ = dag.AddEdge("1", "6")
+	_ = dag.AddEdge("6", "4")
+	_ = dag.AddEdge("7", "6")
+	stop := func(id string, v interface{}) bool { return v == "vbarrier" }
+
+	descendants, err := dag.GetDescendantsUntil("2", stop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{"barrier": "vbarrier"}
+	if diff := deep.Equal(descendants, expected); diff != nil {
+		t.Errorf("GetDescendantsUntil(2) = %v, want %v", descendants, expected)
+	}
+
+	// 4 and 5 are reachable via 6
+	descendants, _ = dag.GetDescendantsUntil("1", stop)
+	expected = map[string]interface{}{"2": "v2", "barrier": "vbarrier", "6": "v6", "4": "v4", "5": "v5"}
+	if diff := deep.Equal(descendants, expected); diff != nil {
+		t.Errorf("GetDescendantsUntil(1) = %v, want %v", descendants, expected)
+	}
+	_ = dag.DeleteEdge("6", "4")
+	descendants, _ = dag.GetDescendantsUntil("1", stop)
+	expected = map[string]interface{}{"2": "v2", "barrier": "vbarrier", "6": "v6"}
+	if diff := deep.Equal(descendants, expected); diff != nil {
+		t.Errorf("GetDescendantsUntil(1) = %v, want %v", descendants, expected)
+	}
+
+	ancestors, _ := dag.GetAncestorsUntil("5", stop)
+	expected = map[string]interface{}{"4": "v4", "barrier": "vbarrier"}
+	if diff := deep.Equal(ancestors, expected); diff != nil {
+		t.Errorf("GetAncestorsUntil(5) = %v, want %v", ancestors, expected)
+	}
+	ancestors, _ = dag.GetAncestorsUntil("6", stop)
+	expected = map[string]interface{}{"1": "v1", "7": "v7"}
+	if diff := deep.Equal(ancestors, expected); diff != nil {
+		t.Errorf("GetAncestorsUntil(6) = %v, want %v", ancestors, expected)
+	}
+
+	// unknown
+	_, errUnknown := dag.GetDescendantsUntil("foo", stop)
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("GetDescendantsUntil(\"foo\") expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_IsDescendant(t *testing.T) {
+	dag := NewDAG()
+	v1, _ := dag.AddVertex("1")
+	v2, _ := dag.AddVertex("2")
+	v3, _ := dag.AddVertex("3")
+	v4, _ := dag.AddVertex("4")
+	_ = dag.AddEdge(v1, v2)
+	_ = dag.AddEdge(v2, v3)
+	dag.FlushCaches()
+
+	if isDescendant, _ := dag.IsDescendant(v1, v3); !isDescendant {
+		t.Errorf("IsDescendant(v1, v3) = false, want true")
+	}
+	if isDescendant, _ := dag.IsDescendant(v3, v1); isDescendant {
+		t.Errorf("IsDescendant(v3, v1) = true, want false")
+	}
+	if isDescendant, _ := dag.IsDescendant(v1, v4); isDescendant {
+		t.Errorf("IsDescendant(v1, v4) = true, want false")
+	}
+	if len(dag.descendantsCache) != 0 {
+		t.Errorf("len(descendantsCache) = %d, want 0", len(dag.descendantsCache))
+	}
+
+	// equal
+	_, errEqual := dag.IsDescendant(v1, v1)
+	if _, ok := errEqual.(SrcDstEqualError); !ok {
+		t.Errorf("IsDescendant(v1, v1) expected SrcDstEqualError, got %T", errEqual)
+	}
+
+	// nil
+	_, errNil := dag.IsDescendant("", v1)
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("IsDescendant(\"\", v1) expected IDEmptyError, got %T", errNil)
+	}
+
+	// unknown
+	_, errUnknown := dag.IsDescendant(v1, "foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("IsDescendant(v1, \"foo\") expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_FilterDescendants(t *testing.T) {
+	dag := getTestWalkDAG()
+
+	// 1 --> 2 --> 3, 2 --> 4 --> 5
+	got, err := dag.FilterDescendants("2", []string{"1", "2", "3", "5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"1": false, "2": false, "3": true, "5": true}
+	if deep.Equal(got, want) != nil {
+		t.Errorf("FilterDescendants(2) = %v, want %v", got, want)
+	}
+	if got, _ := dag.FilterDescendants("5", nil); len(got) != 0 {
+		t.Errorf("FilterDescendants(5, nil) = %v, want empty", got)
+	}
+
+	// unknown
+	_, errUnknown := dag.FilterDescendants("2", []string{"3", "foo"})
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("FilterDescendants(2, [3 foo]) expected IDUnknownError, got %T", errUnknown)
+	}
+	_, errEmpty := dag.FilterDescendants("", []string{"3"})
+	if _, ok := errEmpty.(IDEmptyError); !ok {
+		t.Errorf("FilterDescendants(\"\", [3]) expected IDEmptyError, got %T", errEmpty)
+	}
+}
+
+func TestDAG_IsAncestor(t *testing.T) {
+	dag := NewDAG()
+	v1, _ := dag.AddVertex("1")
+	v2, _ := dag.AddVertex("2")
+	v3, _ := dag.AddVertex("3")
+	v4, _ := dag.AddVertex("4")
+	_ = dag.AddEdge(v1, v2)
+	_ = dag.AddEdge(v2, v3)
+	dag.FlushCaches()
+
+	if isAncestor, _ := dag.IsAncestor(v3, v1); !isAncestor {
+		t.Errorf("IsAncestor(v3, v1) = false, want true")
+	}
+	if isAncestor, _ := dag.IsAncestor(v1, v3); isAncestor {
+		t.Errorf("IsAncestor(v1, v3) = true, want false")
+	}
+	if isAncestor, _ := dag.IsAncestor(v3, v4); isAncestor {
+		t.Errorf("IsAncestor(v3, v4) = true, want false")
+	}
+	if len(dag.ancestorsCache) != 0 {
+		t.Errorf("len(ancestorsCache) = %d, want 0", len(dag.ancestorsCache))
+	}
+
+	// equal
+	_, errEqual := dag.IsAncestor(v1, v1)
+	if _, ok := errEqual.(SrcDstEqualError); !ok {
+		t.Errorf("IsAncestor(v1, v1) expected SrcDstEqualError, got %T", errEqual)
+	}
+
+	// nil
+	_, errNil := dag.IsAncestor(v1, "")
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("IsAncestor(v1, \"\") expected IDEmptyError, got %T", errNil)
+	}
+
+	// unknown
+	_, errUnknown := dag.IsAncestor("foo", v1)
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("IsAncestor(\"foo\", v1) expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
 func equal(a, b []string) bool {
 	if len(a) != len(b) {
 		return false
@@ -697,6 +2005,44 @@ func TestDAG_GetOrderedDescendants(t *testing.T) {
 	}
 }
 
+func TestDAG_GetOrderedDescendantsWithDepth(t *testing.T) {
+	dag := getTestPathDAG()
+
+	descendants, err := dag.GetOrderedDescendantsWithDepth("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []VertexDepth{{"2", 1}, {"3", 1}, {"6", 1}, {"4", 2}, {"5", 2}, {"7", 3}}
+	if diff := deep.Equal(descendants, expected); diff != nil {
+		t.Errorf("GetOrderedDescendantsWithDepth(1) = %v, want %v", descendants, expected)
+	}
+
+	// diamond with branches of different length
+	descendants, _ = dag.GetOrderedDescendantsWithDepth("2")
+	expected = []VertexDepth{{"4", 1}, {"5", 2}, {"6", 2}, {"7", 3}}
+	if diff := deep.Equal(descendants, expected); diff != nil {
+		t.Errorf("GetOrderedDescendantsWithDepth(2) = %v, want %v", descendants, expected)
+	}
+
+	// leaf
+	descendants, _ = dag.GetOrderedDescendantsWithDepth("7")
+	if len(descendants) != 0 {
+		t.Errorf("GetOrderedDescendantsWithDepth(7) = %v, want []", descendants)
+	}
+
+	// nil
+	_, errNil := dag.GetOrderedDescendantsWithDepth("")
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("GetOrderedDescendantsWithDepth(\"\") expected IDEmptyError, got %T", errNil)
+	}
+
+	// unknown
+	_, errUnknown := dag.GetOrderedDescendantsWithDepth("foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("GetOrderedDescendantsWithDepth(\"foo\") expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
 func TestDAG_GetDescendantsGraph(t *testing.T) {
 	d0 := NewDAG()
 
@@ -898,6 +2244,91 @@ func TestDAG_GetAncestorsGraph(t *testing.T) {
 	}
 }
 
+func TestDAG_GetRelativesGraphMaxDepth(t *testing.T) {
+	d0 := NewDAG()
+	for i := 1; i <= 8; i++ {
+		_, _ = d0.AddVertex(iVertex{i})
+	}
+	_ = d0.AddEdge("1", "2")
+	_ = d0.AddEdge("2", "3")
+	_ = d0.AddEdge("2", "4")
+	_ = d0.AddEdge("3", "5")
+	_ = d0.AddEdge("4", "5")
+	_ = d0.AddEdge("5", "6")
+	_ = d0.AddEdge("6", "7")
+	_ = d0.AddEdge("6", "8")
+	_ = d0.AddEdge("1", "5")
+
+	cases := []struct {
+		asc      bool
+		depth    int
+		order    int
+		size     int
+		vertices []string
+	}{
+		{false, 0, 1, 0, []string{"2"}},
+		{false, 1, 3, 2, []string{"2", "3", "4"}},
+		{false, 2, 4, 4, []string{"2", "3", "4", "5"}},
+		{false, 10, 7, 7, []string{"2", "3", "4", "5", "6", "7", "8"}},
+		{true, 1, 4, 3, []string{"1", "3", "4", "5"}},
+		{true, 2, 5, 6, []string{"1", "2", "3", "4", "5"}},
+	}
+	for _, c := range cases {
+		var d *DAG
+		var newID string
+		var err error
+		start := "2"
+		if c.asc {
+			start = "5"
+			d, newID, err = d0.GetAncestorsGraphMaxDepth(start, c.depth)
+		} else {
+			d, newID, err = d0.GetDescendantsGraphMaxDepth(start, c.depth)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if newID != start {
+			t.Errorf("GetRelativesGraphMaxDepth(%s, %d) returned id %s, want %s", start, c.depth, newID, start)
+		}
+		if d.GetOrder() != c.order {
+			t.Errorf("GetRelativesGraphMaxDepth(%s, %d).GetOrder() = %d, want %d", start, c.depth, d.GetOrder(), c.order)
+		}
+		if d.GetSize() != c.size {
+			t.Errorf("GetRelativesGraphMaxDepth(%s, %d).GetSize() = %d, want %d", start, c.depth, d.GetSize(), c.size)
+		}
+		if actual := d.SortedVertexIDs(); !equal(actual, c.vertices) {
+			t.Errorf("GetRelativesGraphMaxDepth(%s, %d) = %v, want %v", start, c.depth, actual, c.vertices)
+		}
+	}
+
+	// like GetDescendantsGraph, vertices without IDInterface get new ids
+	d1 := NewDAG()
+	_ = d1.AddVertexByID("1", 1)
+	_ = d1.AddVertexByID("2", 2)
+	_ = d1.AddVertexByID("3", 3)
+	_ = d1.AddEdge("1", "2")
+	_ = d1.AddEdge("2", "3")
+	d, newID, err := d1.GetDescendantsGraphMaxDepth("1", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := d.GetVertex(newID); v != 1 {
+		t.Errorf("GetDescendantsGraphMaxDepth(1, 1) returned id of %v, want id of 1", v)
+	}
+	if children, _ := d.GetChildren(newID); len(children) != 1 || d.GetOrder() != 2 {
+		t.Errorf("GetDescendantsGraphMaxDepth(1, 1) = %v, want 1 -> 2", d.String())
+	}
+	if _, fullID, _ := d1.GetDescendantsGraph("1"); (newID == "1") != (fullID == "1") {
+		t.Errorf("GetDescendantsGraphMaxDepth(1, 1) returned id %s, GetDescendantsGraph(1) returned id %s", newID, fullID)
+	}
+
+	// unknown
+	_, _, errUnknown := d0.GetDescendantsGraphMaxDepth("foo", 1)
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("GetDescendantsGraphMaxDepth(\"foo\", 1) expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
 func TestDAG_GetAncestors(t *testing.T) {
 	dag := NewDAG()
 	v0, _ := dag.AddVertex("0")
@@ -1063,32 +2494,266 @@ func TestDAG_AncestorsWalker(t *testing.T) {
 	}
 }
 
+func TestDAG_AncestorsWalkerMaxDepth(t *testing.T) {
+	dag := getTestPathDAG()
+
+	cases := []struct {
+		maxDepth int
+		expected []string
+	}{
+		{1, []string{"5"}},
+		{2, []string{"3", "4", "5"}},
+		{3, []string{"1", "2", "3", "4", "5"}},
+		{4, []string{"1", "2", "3", "4", "5"}},
+		{0, []string{"1", "2", "3", "4", "5"}},
+		{-1, []string{"1", "2", "3", "4", "5"}},
+	}
+	for _, c := range cases {
+		ids, _, err := dag.AncestorsWalkerMaxDepth("7", c.maxDepth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ancestors []string
+		for id := range ids {
+			ancestors = append(ancestors, id)
+		}
+		sort.Strings(ancestors)
+		if !equal(ancestors, c.expected) {
+			t.Errorf("AncestorsWalkerMaxDepth(7, %d) = %v, want %v", c.maxDepth, ancestors, c.expected)
+		}
+	}
+
+	// nil
+	_, _, errNil := dag.AncestorsWalkerMaxDepth("", 1)
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("AncestorsWalkerMaxDepth(\"\", 1) expected IDEmptyError, got %T", errNil)
+	}
+}
+
 func TestDAG_AncestorsWalkerSignal(t *testing.T) {
 	dag := NewDAG()
 
 	v1, _ := dag.AddVertex("1")
 	v2, _ := dag.AddVertex("2")
 	v3, _ := dag.AddVertex("3")
-	v4, _ := dag.AddVertex("4")
-	v5, _ := dag.AddVertex("5")
+	v4, _ := dag.AddVertex("4")
+	v5, _ := dag.AddVertex("5")
+	_ = dag.AddEdge(v1, v2)
+	_ = dag.AddEdge(v2, v3)
+	_ = dag.AddEdge(v2, v4)
+	_ = dag.AddEdge(v4, v5)
+
+	var ancestors []string
+	vertices, signal, _ := dag.AncestorsWalker(v5)
+	for v := range vertices {
+		ancestors = append(ancestors, v)
+		if v == v2 {
+			signal <- true
+			break
+		}
+	}
+	if !equal(ancestors, []string{v4, v2}) {
+		t.Errorf("AncestorsWalker(v4) = %v, want %v", ancestors, []string{v4, v2})
+	}
+
+}
+
+func TestDAG_AncestorsWalkerSignalLast(t *testing.T) {
+	dag := NewDAG()
+
+	v1, _ := dag.AddVertex("1")
+	v2, _ := dag.AddVertex("2")
+	v3, _ := dag.AddVertex("3")
+	_ = dag.AddEdge(v1, v2)
+	_ = dag.AddEdge(v2, v3)
+
+	var ancestors []string
+	vertices, signal, _ := dag.AncestorsWalker(v3)
+	for v := range vertices {
+		ancestors = append(ancestors, v)
+		if v == v1 {
+			break
+		}
+	}
+
+	// wait for the walker to finish and signal afterwards
+	for range vertices {
+	}
+	signal <- true
+
+	if !equal(ancestors, []string{v2, v1}) {
+		t.Errorf("AncestorsWalker(v3) = %v, want %v", ancestors, []string{v2, v1})
+	}
+
+	// signal in the middle of the walk (the walker must not block the graph)
+	vertices, signal, _ = dag.AncestorsWalker(v3)
+	for v := range vertices {
+		if v == v2 {
+			signal <- true
+			break
+		}
+	}
+	done := make(chan error)
+	go func() { done <- dag.AddEdge(v1, v3) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("AddEdge() blocked after stopping AncestorsWalker")
+	}
+}
+
+func TestDAG_DescendantsWalkerSignalLast(t *testing.T) {
+	dag := NewDAG()
+
+	v1, _ := dag.AddVertex("1")
+	v2, _ := dag.AddVertex("2")
+	v3, _ := dag.AddVertex("3")
 	_ = dag.AddEdge(v1, v2)
 	_ = dag.AddEdge(v2, v3)
-	_ = dag.AddEdge(v2, v4)
-	_ = dag.AddEdge(v4, v5)
 
-	var ancestors []string
-	vertices, signal, _ := dag.AncestorsWalker(v5)
+	var descendants []string
+	vertices, signal, _ := dag.DescendantsWalker(v1)
+	for v := range vertices {
+		descendants = append(descendants, v)
+		if v == v3 {
+			break
+		}
+	}
+
+	// wait for the walker to finish and signal afterwards
+	for range vertices {
+	}
+	signal <- true
+
+	if !equal(descendants, []string{v2, v3}) {
+		t.Errorf("DescendantsWalker(v1) = %v, want %v", descendants, []string{v2, v3})
+	}
+
+	// signal in the middle of the walk (the walker must not block the graph)
+	vertices, signal, _ = dag.DescendantsWalker(v1)
 	for v := range vertices {
-		ancestors = append(ancestors, v)
 		if v == v2 {
 			signal <- true
 			break
 		}
 	}
-	if !equal(ancestors, []string{v4, v2}) {
-		t.Errorf("AncestorsWalker(v4) = %v, want %v", ancestors, []string{v4, v2})
+	done := make(chan error)
+	go func() { done <- dag.AddEdge(v1, v3) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("AddEdge() blocked after stopping DescendantsWalker")
+	}
+}
+
+func TestDAG_DescendantsChannelV(t *testing.T) {
+	dag := NewDAG()
+	_ = dag.AddVertexByID("1", "v1")
+	_ = dag.AddVertexByID("2", "v2")
+	_ = dag.AddVertexByID("3", "v3")
+	_ = dag.AddVertexByID("4", "v4")
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("2", "3")
+	_ = dag.AddEdge("3", "4")
+
+	vertices, _, err := dag.DescendantsChannelV("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values []string
+	for v := range vertices {
+		id, value := v.Vertex()
+		if value != "v"+id {
+			t.Errorf("DescendantsChannelV() returned %v for %s, want %v", value, id, "v"+id)
+		}
+		values = append(values, value.(string))
+	}
+	if !equal(values, []string{"v2", "v3", "v4"}) {
+		t.Errorf("DescendantsChannelV(1) = %v, want %v", values, []string{"v2", "v3", "v4"})
+	}
+
+	// stop after the first vertex
+	vertices, signal, _ := dag.DescendantsChannelV("1")
+	var count int
+	for range vertices {
+		count++
+		signal <- true
+	}
+	if count > 2 {
+		t.Errorf("DescendantsChannelV(1) returned %d vertices after the signal, want at most 2", count)
 	}
+	// the signal channel is never closed, so signaling again is safe
+	signal <- true
 
+	// unknown id
+	_, _, err = dag.DescendantsChannelV("foo")
+	if _, ok := err.(IDUnknownError); !ok {
+		t.Errorf("DescendantsChannelV(foo) expected IDUnknownError, got %T", err)
+	}
+}
+
+func TestDAG_WalkerContext(t *testing.T) {
+	dag := NewDAG()
+	_ = dag.AddVertexByID("0", 0)
+	for i := 1; i < 10; i++ {
+		_ = dag.AddVertexByID(strconv.Itoa(i), i)
+		_ = dag.AddEdge(strconv.Itoa(i-1), strconv.Itoa(i))
+	}
+
+	// complete walks
+	ids, err := dag.DescendantsWalkerContext(context.Background(), "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var descendants []string
+	for id := range ids {
+		descendants = append(descendants, id)
+	}
+	if len(descendants) != 9 {
+		t.Errorf("DescendantsWalkerContext(0) = %v, want 9 vertices", descendants)
+	}
+	ids, _ = dag.AncestorsWalkerContext(context.Background(), "9")
+	var ancestors []string
+	for id := range ids {
+		ancestors = append(ancestors, id)
+	}
+	if len(ancestors) != 9 {
+		t.Errorf("AncestorsWalkerContext(9) = %v, want 9 vertices", ancestors)
+	}
+
+	// cancel in the middle of the walk
+	ctx, cancel := context.WithCancel(context.Background())
+	ids, _ = dag.DescendantsWalkerContext(ctx, "0")
+	if id := <-ids; id != "1" {
+		t.Errorf("DescendantsWalkerContext(0) returned %s first, want 1", id)
+	}
+	cancel()
+
+	// the walker must exit (i.e. close the channel and release the graph)
+	done := make(chan struct{})
+	go func() {
+		for range ids {
+		}
+		_ = dag.AddEdge("0", "9")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("DescendantsWalkerContext() did not exit after cancel")
+	}
+
+	// unknown
+	_, errUnknown := dag.DescendantsWalkerContext(context.Background(), "foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("DescendantsWalkerContext(foo) expected IDUnknownError, got %T", errUnknown)
+	}
 }
 
 func TestDAG_ReduceTransitively(t *testing.T) {
@@ -1144,6 +2809,186 @@ func TestDAG_ReduceTransitively(t *testing.T) {
 	}
 }
 
+func TestDAG_TransitiveReductionCopy(t *testing.T) {
+	dag := NewDAG()
+	accountCreate, _ := dag.AddVertex("AccountCreate")
+	projectCreate, _ := dag.AddVertex("ProjectCreate")
+	mailSend, _ := dag.AddVertex("MailSend")
+
+	_ = dag.AddEdge(accountCreate, projectCreate)
+	_ = dag.AddEdge(accountCreate, mailSend)
+	_ = dag.AddWeightedEdge(projectCreate, mailSend, 3)
+
+	reduced, err := dag.TransitiveReductionCopy()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the original is unchanged
+	if size := dag.GetSize(); size != 3 {
+		t.Errorf("GetSize() = %d, want 3", size)
+	}
+	if isEdge, _ := dag.IsEdge(accountCreate, mailSend); !isEdge {
+		t.Errorf("IsEdge(accountCreate, mailSend) = %t, want %t", isEdge, true)
+	}
+
+	// the copy is reduced (and has the same ids)
+	if order := reduced.GetOrder(); order != 3 {
+		t.Errorf("GetOrder() = %d, want 3", order)
+	}
+	if size := reduced.GetSize(); size != 2 {
+		t.Errorf("GetSize() = %d, want 2", size)
+	}
+	if isEdge, _ := reduced.IsEdge(accountCreate, mailSend); isEdge {
+		t.Errorf("IsEdge(accountCreate, mailSend) = %t, want %t", isEdge, false)
+	}
+	if weight, _ := reduced.GetEdgeWeight(projectCreate, mailSend); weight != 3 {
+		t.Errorf("GetEdgeWeight(projectCreate, mailSend) = %v, want 3", weight)
+	}
+}
+
+func TestDAG_ReduceTransitivelyRemoved(t *testing.T) {
+	dag := NewDAG()
+	accountCreate, _ := dag.AddVertex("AccountCreate")
+	projectCreate, _ := dag.AddVertex("ProjectCreate")
+	networkCreate, _ := dag.AddVertex("NetworkCreate")
+	contactCreate, _ := dag.AddVertex("ContactCreate")
+	authCreate, _ := dag.AddVertex("AuthCreate")
+	mailSend, _ := dag.AddVertex("MailSend")
+
+	_ = dag.AddEdge(accountCreate, projectCreate)
+	_ = dag.AddEdge(accountCreate, networkCreate)
+	_ = dag.AddEdge(accountCreate, contactCreate)
+	_ = dag.AddEdge(accountCreate, authCreate)
+	_ = dag.AddEdge(accountCreate, mailSend)
+
+	_ = dag.AddEdge(projectCreate, mailSend)
+	_ = dag.AddEdge(networkCreate, mailSend)
+	_ = dag.AddEdge(contactCreate, mailSend)
+	_ = dag.AddEdge(authCreate, mailSend)
+
+	removed := dag.ReduceTransitivelyRemoved()
+	expected := [][2]string{{accountCreate, mailSend}}
+	if diff := deep.Equal(removed, expected); diff != nil {
+		t.Errorf("ReduceTransitivelyRemoved() = %v, want %v", removed, expected)
+	}
+	if size := dag.GetSize(); size != 8 {
+		t.Errorf("GetSize() = %d, want 8", size)
+	}
+
+	// nothing left to remove
+	if removed := dag.ReduceTransitivelyRemoved(); len(removed) != 0 {
+		t.Errorf("ReduceTransitivelyRemoved() = %v, want []", removed)
+	}
+
+	// multiple edges are ordered
+	dag = getTestPathDAG()
+	_ = dag.AddEdge("1", "7")
+	_ = dag.AddEdge("1", "5")
+	removed = dag.ReduceTransitivelyRemoved()
+	expected = [][2]string{{"1", "5"}, {"1", "6"}, {"1", "7"}}
+	if diff := deep.Equal(removed, expected); diff != nil {
+		t.Errorf("ReduceTransitivelyRemoved() = %v, want %v", removed, expected)
+	}
+}
+
+func TestDAG_ReachablePairs(t *testing.T) {
+	dag := NewDAG()
+	for _, id := range []string{"1", "2", "3", "4"} {
+		_ = dag.AddVertexByID(id, "v"+id)
+	}
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("2", "3")
+	_ = dag.AddEdge("3", "4")
+
+	want := [][2]string{{"1", "2"}, {"1", "3"}, {"1", "4"}, {"2", "3"}, {"2", "4"}, {"3", "4"}}
+	if got := dag.ReachablePairs(); deep.Equal(got, want) != nil {
+		t.Errorf("ReachablePairs() = %v, want %v", got, want)
+	}
+	if got := NewDAG().ReachablePairs(); len(got) != 0 {
+		t.Errorf("ReachablePairs() = %v, want []", got)
+	}
+}
+
+func TestDAG_TransitiveClosure(t *testing.T) {
+	dag := NewDAG()
+	for _, id := range []string{"1", "2", "3", "4"} {
+		_ = dag.AddVertexByID(id, "v"+id)
+	}
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("2", "3")
+	_ = dag.AddEdge("3", "4")
+
+	closure, err := dag.TransitiveClosure()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order := closure.GetOrder(); order != 4 {
+		t.Errorf("GetOrder() = %d, want 4", order)
+	}
+	if size := closure.GetSize(); size != 6 {
+		t.Errorf("GetSize() = %d, want 6", size)
+	}
+	for _, e := range [][2]string{{"1", "2"}, {"1", "3"}, {"1", "4"}, {"2", "3"}, {"2", "4"}, {"3", "4"}} {
+		if isEdge, _ := closure.IsEdge(e[0], e[1]); !isEdge {
+			t.Errorf("IsEdge(%s, %s) = false, want true", e[0], e[1])
+		}
+	}
+
+	// the original is untouched
+	if size := dag.GetSize(); size != 3 {
+		t.Errorf("GetSize() = %d, want 3", size)
+	}
+
+	// reducing the closure yields the original
+	closure.ReduceTransitively()
+	if !closure.Equal(dag) {
+		t.Errorf("ReduceTransitively() = %v, want %v", closure.String(), dag.String())
+	}
+}
+
+func TestDAG_TopologicalSort(t *testing.T) {
+	dag := NewDAG()
+
+	// empty graph
+	sorted, err := dag.TopologicalSort()
+	if err != nil {
+		t.Error(err)
+	}
+	if len(sorted) != 0 {
+		t.Errorf("TopologicalSort() = %v, want []", sorted)
+	}
+
+	// two roots (a, b) converging on c, a disconnected component (e -> f),
+	// and an isolated vertex (g)
+	for _, id := range []string{"g", "f", "e", "d", "c", "b", "a"} {
+		_ = dag.AddVertexByID(id, id)
+	}
+	_ = dag.AddEdge("a", "c")
+	_ = dag.AddEdge("b", "c")
+	_ = dag.AddEdge("c", "d")
+	_ = dag.AddEdge("e", "f")
+
+	expected := []string{"a", "b", "c", "d", "e", "f", "g"}
+	for i := 0; i < 10; i++ {
+		sorted, err = dag.TopologicalSort()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equal(sorted, expected) {
+			t.Errorf("TopologicalSort() = %v, want %v", sorted, expected)
+		}
+	}
+
+	// a later vertex with a lower id must not overtake its parents
+	_ = dag.AddVertexByID("0", "0")
+	_ = dag.AddEdge("d", "0")
+	expected = []string{"a", "b", "c", "d", "0", "e", "f", "g"}
+	if sorted, _ = dag.TopologicalSort(); !equal(sorted, expected) {
+		t.Errorf("TopologicalSort() = %v, want %v", sorted, expected)
+	}
+}
+
 func TestDAG_Copy(t *testing.T) {
 	d0 := NewDAG()
 
@@ -1166,34 +3011,287 @@ func TestDAG_Copy(t *testing.T) {
 	_ = d0.AddEdge("6", "7")
 	_ = d0.AddEdge("6", "8")
 
-	d1, err := d0.Copy()
-	if err != nil {
-		t.Error(err)
+	d1, err := d0.Copy()
+	if err != nil {
+		t.Error(err)
+	}
+	if d1.GetOrder() != d0.GetOrder() {
+		t.Errorf("got %d, want %d", d1.GetOrder(), d0.GetOrder())
+	}
+	if d1.GetSize() != d0.GetSize() {
+		t.Errorf("got %d, want %d", d1.GetSize(), d0.GetSize())
+	}
+	if len(d1.GetRoots()) != len(d0.GetRoots()) {
+		t.Errorf("got %d, want %d", len(d1.GetRoots()), len(d0.GetRoots()))
+	}
+	if len(d1.GetLeaves()) != len(d0.GetLeaves()) {
+		t.Errorf("got %d, want %d", len(d1.GetLeaves()), len(d0.GetLeaves()))
+	}
+	for i := 1; i < 9; i++ {
+		v1, errGet1 := d1.GetVertex(strconv.Itoa(i))
+		if errGet1 != nil {
+			t.Error(errGet1)
+		}
+		v2, errGet2 := d1.GetVertex(strconv.Itoa(i))
+		if errGet2 != nil {
+			t.Error(errGet2)
+		}
+		if v2 != v1 {
+			t.Errorf("got %v, want %v", v2, v1)
+		}
+	}
+}
+
+func TestDAG_CopyEdgeAttributes(t *testing.T) {
+	d0 := NewDAG()
+	_, _ = d0.AddVertex(iVertex{1})
+	_, _ = d0.AddVertex(iVertex{2})
+	_, _ = d0.AddVertex(iVertex{3})
+	_ = d0.AddWeightedEdge("1", "2", 2)
+	_ = d0.AddEdge("2", "3")
+	_ = d0.SetEdgeLabel("2", "3", "l23")
+
+	d1, err := d0.Copy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weight, _ := d1.GetEdgeWeight("1", "2"); weight != 2 {
+		t.Errorf("GetEdgeWeight(1, 2) = %v, want 2", weight)
+	}
+	if label, _ := d1.GetEdgeLabel("2", "3"); label != "l23" {
+		t.Errorf("GetEdgeLabel(2, 3) = %v, want l23", label)
+	}
+
+	// changing the copy must not affect the original
+	_ = d1.DeleteEdge("1", "2")
+	_ = d1.AddWeightedEdge("1", "2", 5)
+	_ = d1.SetEdgeLabel("2", "3", "changed")
+	if weight, _ := d0.GetEdgeWeight("1", "2"); weight != 2 {
+		t.Errorf("GetEdgeWeight(1, 2) = %v, want 2", weight)
+	}
+	if label, _ := d0.GetEdgeLabel("2", "3"); label != "l23" {
+		t.Errorf("GetEdgeLabel(2, 3) = %v, want l23", label)
+	}
+}
+
+func TestDAG_CopyWithTransform(t *testing.T) {
+	dag := NewDAG()
+	for i := 1; i <= 4; i++ {
+		_ = dag.AddVertexByID(strconv.Itoa(i), i)
+	}
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("1", "3")
+	_ = dag.AddEdge("3", "4")
+
+	double := func(_ string, v interface{}) interface{} { return v.(int) * 2 }
+	copied, err := dag.CopyWithTransform(double)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 4; i++ {
+		id := strconv.Itoa(i)
+		if v, _ := copied.GetVertex(id); v != 2*i {
+			t.Errorf("GetVertex(%s) = %v, want %d", id, v, 2*i)
+		}
+	}
+	expected := [][2]string{{"1", "2"}, {"1", "3"}, {"3", "4"}}
+	if size := copied.GetSize(); size != len(expected) {
+		t.Errorf("GetSize() = %d, want %d", size, len(expected))
+	}
+	for _, e := range expected {
+		if isEdge, _ := copied.IsEdge(e[0], e[1]); !isEdge {
+			t.Errorf("IsEdge(%s, %s) = false, want true", e[0], e[1])
+		}
+	}
+
+	// the original is untouched
+	if v, _ := dag.GetVertex("4"); v != 4 {
+		t.Errorf("GetVertex(4) = %v, want 4", v)
+	}
+
+	// duplicate values
+	_, errDuplicate := dag.CopyWithTransform(func(string, interface{}) interface{} { return 1 })
+	if _, ok := errDuplicate.(VertexDuplicateError); !ok {
+		t.Errorf("CopyWithTransform() expected VertexDuplicateError, got %T", errDuplicate)
+	}
+
+	// nil
+	_, errNil := dag.CopyWithTransform(func(string, interface{}) interface{} { return nil })
+	if _, ok := errNil.(VertexNilError); !ok {
+		t.Errorf("CopyWithTransform() expected VertexNilError, got %T", errNil)
+	}
+}
+
+func TestDAG_Equal(t *testing.T) {
+	dag := getTestWalkDAG()
+	if !dag.Equal(dag) {
+		t.Errorf("Equal() = false, want true")
+	}
+	if dag.Equal(nil) {
+		t.Errorf("Equal(nil) = true, want false")
+	}
+
+	// same graph built in a different order and with a populated cache
+	other := NewDAG()
+	for _, id := range []string{"5", "4", "3", "2", "1"} {
+		_ = other.AddVertexByID(id, "v"+id)
+	}
+	_ = other.AddEdge("4", "5")
+	_ = other.AddEdge("2", "4")
+	_ = other.AddEdge("2", "3")
+	_ = other.AddEdge("1", "2")
+	_, _ = other.GetDescendants("1")
+	if !dag.Equal(other) || !other.Equal(dag) {
+		t.Errorf("Equal() = false, want true")
+	}
+
+	// different edge
+	_ = other.DeleteEdge("2", "3")
+	_ = other.AddEdge("1", "3")
+	if dag.Equal(other) {
+		t.Errorf("Equal() = true, want false")
+	}
+
+	// different value
+	other = getTestWalkDAG()
+	_ = other.ReplaceVertexValue("3", "foo")
+	if dag.Equal(other) {
+		t.Errorf("Equal() = true, want false")
+	}
+
+	// additional vertex
+	other = getTestWalkDAG()
+	_ = other.AddVertexByID("6", "v6")
+	if dag.Equal(other) || other.Equal(dag) {
+		t.Errorf("Equal() = true, want false")
+	}
+
+	// different id
+	other = getTestWalkDAG()
+	_ = other.DeleteVertex("3")
+	_ = other.AddVertexByID("6", "v3")
+	_ = other.AddEdge("2", "6")
+	if dag.Equal(other) {
+		t.Errorf("Equal() = true, want false")
+	}
+}
+
+func TestDAG_EqualConcurrent(t *testing.T) {
+	testCompareConcurrent(t, func(a, b *DAG) { _ = a.Equal(b) })
+}
+
+// testCompareConcurrent checks, that compare(a, b) doesn't hold the lock of a
+// while waiting for the lock of b (e.g. due to a pending writer). Otherwise,
+// compare(a, b) and compare(b, a) may deadlock.
+func testCompareConcurrent(t *testing.T, compare func(a, b *DAG)) {
+	a, b := getTestWalkDAG(), getTestWalkDAG()
+
+	// block new readers of b (by holding a read lock and queuing a writer)
+	b.muDAG.RLock()
+	go func() { _ = b.AddVertexByID("6", "v6") }()
+	time.Sleep(10 * time.Millisecond)
+
+	compared := make(chan struct{})
+	go func() {
+		compare(a, b)
+		close(compared)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	// a must still be writable, while compare waits for b
+	added := make(chan struct{})
+	go func() {
+		_ = a.AddVertexByID("7", "v7")
+		close(added)
+	}()
+	select {
+	case <-added:
+	case <-time.After(time.Second):
+		t.Errorf("comparing holds the lock of one graph while waiting for the other")
+	}
+	b.muDAG.RUnlock()
+	<-compared
+	<-added
+}
+
+func TestDAG_Transpose(t *testing.T) {
+	d0 := getTestWalkDAG()
+	d1, err := d0.Transpose()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d1.GetOrder() != d0.GetOrder() {
+		t.Errorf("GetOrder() = %d, want %d", d1.GetOrder(), d0.GetOrder())
+	}
+	if d1.GetSize() != d0.GetSize() {
+		t.Errorf("GetSize() = %d, want %d", d1.GetSize(), d0.GetSize())
+	}
+	if deep.Equal(d1.GetRoots(), d0.GetLeaves()) != nil {
+		t.Errorf("GetRoots() = %v, want %v", d1.GetRoots(), d0.GetLeaves())
+	}
+	if deep.Equal(d1.GetLeaves(), d0.GetRoots()) != nil {
+		t.Errorf("GetLeaves() = %v, want %v", d1.GetLeaves(), d0.GetRoots())
+	}
+	if isEdge, _ := d1.IsEdge("2", "1"); !isEdge {
+		t.Errorf("IsEdge(2, 1) = false, want true")
+	}
+	if isEdge, _ := d1.IsEdge("1", "2"); isEdge {
+		t.Errorf("IsEdge(1, 2) = true, want false")
+	}
+	if v, _ := d1.GetVertex("3"); v != "v3" {
+		t.Errorf("GetVertex(3) = %v, want v3", v)
+	}
+}
+
+func TestDAG_DiffConcurrent(t *testing.T) {
+	testCompareConcurrent(t, func(a, b *DAG) { _, _, _, _ = a.Diff(b) })
+}
+
+func TestDAG_Diff(t *testing.T) {
+	d1 := NewDAG()
+	for i := 1; i <= 4; i++ {
+		_ = d1.AddVertexByID(strconv.Itoa(i), i)
+	}
+	_ = d1.AddEdge("1", "2")
+	_ = d1.AddEdge("2", "3")
+	_ = d1.AddEdge("3", "4")
+
+	d2, _ := d1.CopyWithTransform(func(id string, v interface{}) interface{} { return v })
+	_ = d2.AddVertexByID("5", 5)
+	_ = d2.AddEdge("4", "5")
+	_ = d2.DeleteVertex("1")
+	_ = d2.DeleteEdge("2", "3")
+
+	addedVertices, removedVertices, addedEdges, removedEdges := d1.Diff(d2)
+	if !equal(addedVertices, []string{"5"}) {
+		t.Errorf("Diff() addedVertices = %v, want %v", addedVertices, []string{"5"})
 	}
-	if d1.GetOrder() != d0.GetOrder() {
-		t.Errorf("got %d, want %d", d1.GetOrder(), d0.GetOrder())
+	if !equal(removedVertices, []string{"1"}) {
+		t.Errorf("Diff() removedVertices = %v, want %v", removedVertices, []string{"1"})
 	}
-	if d1.GetSize() != d0.GetSize() {
-		t.Errorf("got %d, want %d", d1.GetSize(), d0.GetSize())
+	expected := [][2]string{{"4", "5"}}
+	if diff := deep.Equal(addedEdges, expected); diff != nil {
+		t.Errorf("Diff() addedEdges = %v, want %v", addedEdges, expected)
 	}
-	if len(d1.GetRoots()) != len(d0.GetRoots()) {
-		t.Errorf("got %d, want %d", len(d1.GetRoots()), len(d0.GetRoots()))
+	expected = [][2]string{{"1", "2"}, {"2", "3"}}
+	if diff := deep.Equal(removedEdges, expected); diff != nil {
+		t.Errorf("Diff() removedEdges = %v, want %v", removedEdges, expected)
 	}
-	if len(d1.GetLeaves()) != len(d0.GetLeaves()) {
-		t.Errorf("got %d, want %d", len(d1.GetLeaves()), len(d0.GetLeaves()))
+
+	// no differences
+	addedVertices, removedVertices, addedEdges, removedEdges = d1.Diff(d1)
+	if len(addedVertices)+len(removedVertices)+len(addedEdges)+len(removedEdges) != 0 {
+		t.Errorf("Diff() = %v, %v, %v, %v, want no differences", addedVertices, removedVertices, addedEdges, removedEdges)
 	}
-	for i := 1; i < 9; i++ {
-		v1, errGet1 := d1.GetVertex(strconv.Itoa(i))
-		if errGet1 != nil {
-			t.Error(errGet1)
-		}
-		v2, errGet2 := d1.GetVertex(strconv.Itoa(i))
-		if errGet2 != nil {
-			t.Error(errGet2)
-		}
-		if v2 != v1 {
-			t.Errorf("got %v, want %v", v2, v1)
-		}
+
+	// nil is treated as an empty DAG
+	addedVertices, removedVertices, addedEdges, removedEdges = d1.Diff(nil)
+	if len(addedVertices) != 0 || !equal(removedVertices, []string{"1", "2", "3", "4"}) {
+		t.Errorf("Diff(nil) vertices = %v, %v, want [], [1 2 3 4]", addedVertices, removedVertices)
+	}
+	expected = [][2]string{{"1", "2"}, {"2", "3"}, {"3", "4"}}
+	if diff := deep.Equal(removedEdges, expected); len(addedEdges) != 0 || diff != nil {
+		t.Errorf("Diff(nil) edges = %v, %v, want [], %v", addedEdges, removedEdges, expected)
 	}
 }
 
@@ -1213,6 +3311,52 @@ func TestDAG_String(t *testing.T) {
 	}
 }
 
+func TestDMutexPanic(t *testing.T) {
+	dm := newDMutex()
+
+	// a panic while holding the instance lock (the deferred unlock still runs)
+	work := func(i interface{}) {
+		dm.lock(i)
+		defer dm.unlock(i)
+		panic("callback failed")
+	}
+	for j := 0; j < 100; j++ {
+		func() {
+			defer func() { _ = recover() }()
+			work(j % 3)
+		}()
+	}
+	if len(dm.mutexes) != 0 {
+		t.Errorf("len(mutexes) = %d, want 0", len(dm.mutexes))
+	}
+
+	// a panic while holding the global lock (i.e. an unhashable instance)
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("lock() with unhashable instance didn't panic")
+			}
+		}()
+		dm.lock(map[string]int{})
+	}()
+
+	// neither the global nor any instance lock may be left held
+	done := make(chan struct{})
+	go func() {
+		dm.lock(1)
+		dm.unlock(1)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("lock() blocked after a panic")
+	}
+	if len(dm.mutexes) != 0 {
+		t.Errorf("len(mutexes) = %d, want 0", len(dm.mutexes))
+	}
+}
+
 func TestErrors(t *testing.T) {
 
 	tests := []struct {
@@ -1224,7 +3368,8 @@ func TestErrors(t *testing.T) {
 		{"'1' is unknown", IDUnknownError{"1"}},
 		{"edge between '1' and '2' is already known", EdgeDuplicateError{"1", "2"}},
 		{"edge between '1' and '2' is unknown", EdgeUnknownError{"1", "2"}},
-		{"edge between '1' and '2' would create a loop", EdgeLoopError{"1", "2"}},
+		{"edge between '1' and '2' would create a loop", EdgeLoopError{src: "1", dst: "2"}},
+		{"the id '2' doesn't match the expected id '1'", IDMismatchError{"1", "2"}},
 	}
 	for _, tt := range tests {
 		t.Run(fmt.Sprintf("%T", tt.err), func(t *testing.T) {
@@ -1235,6 +3380,114 @@ func TestErrors(t *testing.T) {
 	}
 }
 
+func TestDAG_Freeze(t *testing.T) {
+	dag := getTestWalkDAG()
+	if dag.IsFrozen() {
+		t.Errorf("IsFrozen() = true, want false")
+	}
+	dag.Freeze()
+	if !dag.IsFrozen() {
+		t.Errorf("IsFrozen() = false, want true")
+	}
+
+	mutations := map[string]func() error{
+		"AddEdge":            func() error { return dag.AddEdge("1", "5") },
+		"AddWeightedEdge":    func() error { return dag.AddWeightedEdge("1", "5", 1) },
+		"AddEdges":           func() error { return dag.AddEdges([][2]string{{"1", "5"}}) },
+		"DeleteEdge":         func() error { return dag.DeleteEdge("1", "2") },
+		"ReplaceEdge":        func() error { return dag.ReplaceEdge("1", "2", "5") },
+		"ReParent":           func() error { return dag.ReParent("5", "1") },
+		"ContractEdge":       func() error { return dag.ContractEdge("1", "2") },
+		"SetEdgeLabel":       func() error { return dag.SetEdgeLabel("1", "2", "l") },
+		"SetMetadata":        func() error { return dag.SetMetadata("1", "k", "v") },
+		"AddVertexByID":      func() error { return dag.AddVertexByID("6", "v6") },
+		"ReplaceVertexValue": func() error { return dag.ReplaceVertexValue("1", "v") },
+		"DeleteVertex":       func() error { return dag.DeleteVertex("1") },
+		"DeleteVertices":     func() error { return dag.DeleteVertices([]string{"1"}) },
+		"AddVertex": func() error {
+			_, err := dag.AddVertex("v6")
+			return err
+		},
+		"Prune": func() error {
+			_, err := dag.Prune([]string{"1"})
+			return err
+		},
+	}
+	for name, mutation := range mutations {
+		if err := mutation(); !errors.Is(err, ErrFrozen) {
+			t.Errorf("%s() = %v, want %v", name, err, ErrFrozen)
+		}
+	}
+	if removed := dag.ReduceTransitivelyRemoved(); len(removed) != 0 {
+		t.Errorf("ReduceTransitivelyRemoved() = %v, want []", removed)
+	}
+
+	// the graph is unchanged and queries still work
+	if order, size := dag.GetOrder(), dag.GetSize(); order != 5 || size != 4 {
+		t.Errorf("GetOrder(), GetSize() = %d, %d, want 5, 4", order, size)
+	}
+	if descendants, _ := dag.GetDescendants("1"); len(descendants) != 4 {
+		t.Errorf("len(GetDescendants(1)) = %d, want 4", len(descendants))
+	}
+	if label, _ := dag.GetEdgeLabel("1", "2"); label != nil {
+		t.Errorf("GetEdgeLabel(1, 2) = %v, want nil", label)
+	}
+
+	// copies are not frozen
+	copied, err := dag.Copy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied.IsFrozen() {
+		t.Errorf("Copy().IsFrozen() = true, want false")
+	}
+}
+
+func TestErrorsIs(t *testing.T) {
+	tests := []struct {
+		err      error
+		sentinel error
+	}{
+		{VertexNilError{}, ErrVertexNil},
+		{VertexDuplicateError{"1"}, ErrVertexDuplicate},
+		{VertexUnknownError{"1"}, ErrVertexUnknown},
+		{VertexNotComparableError{"1"}, ErrVertexNotComparable},
+		{IDDuplicateError{"1"}, ErrIDDuplicate},
+		{IDEmptyError{}, ErrIDEmpty},
+		{IDUnknownError{"1"}, ErrIDUnknown},
+		{IDMismatchError{"1", "2"}, ErrIDMismatch},
+		{EdgeDuplicateError{"1", "2"}, ErrEdgeDuplicate},
+		{EdgeUnknownError{"1", "2"}, ErrEdgeUnknown},
+		{EdgeLoopError{src: "1", dst: "2"}, ErrEdgeLoop},
+		{SrcDstEqualError{"1", "1"}, ErrSrcDstEqual},
+		{FrozenError{}, ErrFrozen},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%T", tt.err), func(t *testing.T) {
+			if !errors.Is(tt.err, tt.sentinel) {
+				t.Errorf("errors.Is(%v, %v) = false, want true", tt.err, tt.sentinel)
+			}
+			wrapped := fmt.Errorf("wrapped: %w", tt.err)
+			if !errors.Is(wrapped, tt.sentinel) {
+				t.Errorf("errors.Is(%v, %v) = false, want true", wrapped, tt.sentinel)
+			}
+			if errors.Is(tt.err, ErrVertexNil) != (tt.sentinel == ErrVertexNil) {
+				t.Errorf("errors.Is(%v, %v) = %v, want %v", tt.err, ErrVertexNil, !(tt.sentinel == ErrVertexNil), tt.sentinel == ErrVertexNil)
+			}
+		})
+	}
+
+	// errors returned by the graph
+	dag := NewDAG()
+	_ = dag.AddVertexByID("1", "v1")
+	if _, err := dag.GetVertex("foo"); !errors.Is(err, ErrIDUnknown) {
+		t.Errorf("GetVertex(foo) = %v, want %v", err, ErrIDUnknown)
+	}
+	if err := dag.AddEdge("1", "1"); !errors.Is(err, ErrSrcDstEqual) {
+		t.Errorf("AddEdge(1, 1) = %v, want %v", err, ErrSrcDstEqual)
+	}
+}
+
 func ExampleDAG_AncestorsWalker() {
 	dag := NewDAG()
 
@@ -1331,6 +3584,523 @@ func TestDAG_DescendantsFlowOneNode(t *testing.T) {
 	}
 }
 
+func TestDAG_DescendantsFlowInputs(t *testing.T) {
+	d := NewDAG()
+	v1, _ := d.AddVertex(1)
+	v2, _ := d.AddVertex(2)
+	_ = d.AddEdge(v1, v2)
+
+	var startInputs []FlowResult
+	flowCallback := func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		if id == v1 {
+			startInputs = parentResults
+		}
+		sum := 0
+		for _, r := range parentResults {
+			sum += r.Result.(int)
+		}
+		return sum + 1, nil
+	}
+
+	inputs := []FlowResult{{ID: "a", Result: 10}, {ID: "b", Result: 20}}
+	res, err := d.DescendantsFlow(v1, inputs, flowCallback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deep.Equal(startInputs, inputs) != nil {
+		t.Errorf("parentResults of start vertex = %v, want %v", startInputs, inputs)
+	}
+	if len(res) != 1 || res[0].Result != 32 {
+		t.Errorf("DescendantsFlow() = %v, want a single result 32", res)
+	}
+
+	// without inputs the start vertex gets no parentResults
+	res, _ = d.DescendantsFlow(v1, nil, flowCallback)
+	if len(startInputs) != 0 {
+		t.Errorf("parentResults of start vertex = %v, want []", startInputs)
+	}
+	if len(res) != 1 || res[0].Result != 2 {
+		t.Errorf("DescendantsFlow() = %v, want a single result 2", res)
+	}
+}
+
+func TestDAG_DescendantsFlowExternalParent(t *testing.T) {
+	d := NewDAG()
+	v1, _ := d.AddVertex(1)
+	v2, _ := d.AddVertex(2)
+	v3, _ := d.AddVertex(3)
+	_ = d.AddEdge(v1, v2)
+
+	// v3 is a parent of v2 but not part of the flow starting at v1
+	_ = d.AddEdge(v3, v2)
+
+	flowCallback := func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		return len(parentResults), nil
+	}
+	res, err := d.DescendantsFlow(v1, nil, flowCallback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].ID != v2 || res[0].Result != 1 {
+		t.Errorf("DescendantsFlow() = %v, want [{%s 1 <nil>}]", res, v2)
+	}
+}
+
+func TestResultsByID(t *testing.T) {
+	d := NewDAG()
+	for i := 1; i <= 5; i++ {
+		_ = d.AddVertexByID(strconv.Itoa(i), i)
+	}
+	_ = d.AddEdge("1", "2")
+	_ = d.AddEdge("1", "3")
+	_ = d.AddEdge("1", "4")
+	_ = d.AddEdge("4", "5")
+
+	flowCallback := func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		return "r" + id, nil
+	}
+	res, err := d.DescendantsFlow("1", nil, flowCallback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := ResultsByID(res)
+	expected := map[string]interface{}{"2": "r2", "3": "r3", "5": "r5"}
+	if diff := deep.Equal(byID, expected); diff != nil {
+		t.Errorf("ResultsByID() = %v, want %v", byID, expected)
+	}
+
+	if byID := ResultsByID(nil); len(byID) != 0 {
+		t.Errorf("ResultsByID(nil) = %v, want empty map", byID)
+	}
+}
+
+func TestDAG_FlowEdgeAttributes(t *testing.T) {
+	d := NewDAG()
+	for i := 1; i <= 3; i++ {
+		_ = d.AddVertexByID(strconv.Itoa(i), i)
+	}
+	_ = d.AddWeightedEdge("1", "3", 2)
+	_ = d.AddEdge("2", "3")
+	_ = d.SetEdgeLabel("2", "3", "l23")
+
+	var mu sync.Mutex
+	received := make(map[string][]FlowResult)
+	callback := func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		mu.Lock()
+		received[id] = parentResults
+		mu.Unlock()
+		return id, nil
+	}
+
+	// the inputs and the results of the flow don't carry edge attributes
+	inputs := []FlowResult{{ID: "input"}}
+	results, _ := d.DescendantsFlowMulti([]string{"1", "2"}, inputs, callback)
+	expected := []FlowResult{{ID: "3", Result: "3"}}
+	if diff := deep.Equal(results, expected); diff != nil {
+		t.Errorf("DescendantsFlowMulti() = %v, want %v", results, expected)
+	}
+	parentResults := received["3"]
+	sort.Slice(parentResults, func(i, j int) bool { return parentResults[i].ID < parentResults[j].ID })
+	expected = []FlowResult{{ID: "1", Result: "1", EdgeWeight: 2}, {ID: "2", Result: "2", EdgeWeight: 1, EdgeLabel: "l23"}}
+	if diff := deep.Equal(parentResults, expected); diff != nil {
+		t.Errorf("parentResults = %v, want %v", parentResults, expected)
+	}
+
+	// the same edges in the opposite direction
+	_, _ = d.AncestorsFlow("3", nil, callback)
+	childResults := received["2"]
+	expected = []FlowResult{{ID: "3", Result: "3", EdgeWeight: 1, EdgeLabel: "l23"}}
+	if diff := deep.Equal(childResults, expected); diff != nil {
+		t.Errorf("childResults = %v, want %v", childResults, expected)
+	}
+}
+
+func TestDAG_DescendantsFlowMulti(t *testing.T) {
+
+	//	1 --> 3 --> 4
+	//	|     ^
+	//	v     |
+	//	5     2
+	dag := NewDAG()
+	for i := 1; i <= 5; i++ {
+		_ = dag.AddVertexByID(strconv.Itoa(i), i)
+	}
+	_ = dag.AddEdge("1", "3")
+	_ = dag.AddEdge("2", "3")
+	_ = dag.AddEdge("3", "4")
+	_ = dag.AddEdge("1", "5")
+
+	var mu sync.Mutex
+	executions := make(map[string]int)
+	callback := func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		mu.Lock()
+		executions[id]++
+		mu.Unlock()
+		v, _ := d.vertexIds[id].(int)
+		for _, r := range parentResults {
+			v += r.Result.(int)
+		}
+		return v, nil
+	}
+
+	inputs := []FlowResult{{ID: "input", Result: 10}}
+	results, err := dag.DescendantsFlowMulti([]string{"1", "2"}, inputs, callback)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// each vertex is executed exactly once
+	expectedExecutions := map[string]int{"1": 1, "2": 1, "3": 1, "4": 1, "5": 1}
+	if diff := deep.Equal(executions, expectedExecutions); diff != nil {
+		t.Errorf("executions = %v, want %v", executions, expectedExecutions)
+	}
+
+	// 4 = 4 + 3 + (1 + 10) + (2 + 10), 5 = 5 + (1 + 10)
+	sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+	expected := []FlowResult{{ID: "4", Result: 30}, {ID: "5", Result: 16}}
+	if diff := deep.Equal(results, expected); diff != nil {
+		t.Errorf("DescendantsFlowMulti() = %v, want %v", results, expected)
+	}
+
+	// a start vertex being a descendant of another start vertex
+	executions = make(map[string]int)
+	results, err = dag.DescendantsFlowMulti([]string{"3", "1"}, inputs, callback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+	expected = []FlowResult{{ID: "4", Result: 28}, {ID: "5", Result: 16}}
+	if diff := deep.Equal(results, expected); diff != nil {
+		t.Errorf("DescendantsFlowMulti() = %v, want %v", results, expected)
+	}
+
+	// unknown
+	_, errUnknown := dag.DescendantsFlowMulti([]string{"1", "foo"}, nil, callback)
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("DescendantsFlowMulti([1, foo]) expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_AncestorsFlow(t *testing.T) {
+	d := NewDAG()
+	v1, _ := d.AddVertex(1)
+	v2, _ := d.AddVertex(2)
+	v3, _ := d.AddVertex(3)
+	v4, _ := d.AddVertex(4)
+	_ = d.AddEdge(v1, v3)
+	_ = d.AddEdge(v2, v3)
+
+	// v4 is a child of v1 but not part of the flow starting at v3
+	_ = d.AddEdge(v1, v4)
+
+	flowCallback := func(d *DAG, id string, childResults []FlowResult) (interface{}, error) {
+		v, _ := d.GetVertex(id)
+		result := v.(int)
+		for _, r := range childResults {
+			result += r.Result.(int)
+		}
+		return result, nil
+	}
+	res, err := d.AncestorsFlow(v3, []FlowResult{{ID: "input", Result: 10}}, flowCallback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 {
+		t.Fatalf("len(AncestorsFlow()) = %d, want 2", len(res))
+	}
+	results := map[string]interface{}{res[0].ID: res[0].Result, res[1].ID: res[1].Result}
+	if results[v1] != 14 || results[v2] != 15 {
+		t.Errorf("AncestorsFlow() = %v, want %s: 14 and %s: 15", res, v1, v2)
+	}
+
+	// nil
+	_, errNil := d.AncestorsFlow("", nil, flowCallback)
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("AncestorsFlow(\"\") expected IDEmptyError, got %T", errNil)
+	}
+
+	// unknown
+	_, errUnknown := d.AncestorsFlow("foo", nil, flowCallback)
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("AncestorsFlow(\"foo\") expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_DescendantsFlowContext(t *testing.T) {
+	d := NewDAG()
+	var ids []string
+	for i := 0; i < 10; i++ {
+		id, _ := d.AddVertex(i)
+		ids = append(ids, id)
+		if i > 0 {
+			_ = d.AddEdge(ids[i-1], id)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	calls := 0
+	flowCallback := func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if id == ids[3] {
+			cancel()
+		}
+		return nil, nil
+	}
+
+	_, err := d.DescendantsFlowContext(ctx, ids[0], nil, flowCallback)
+	if err != context.Canceled {
+		t.Errorf("DescendantsFlowContext() = %v, want %v", err, context.Canceled)
+	}
+	if calls != 4 {
+		t.Errorf("calls = %d, want 4", calls)
+	}
+
+	// not canceled
+	calls = 0
+	res, err := d.DescendantsFlowContext(context.Background(), ids[0], nil, flowCallback)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(res) != 1 || calls != 10 {
+		t.Errorf("DescendantsFlowContext() = %d results and %d calls, want 1 and 10", len(res), calls)
+	}
+}
+
+func TestDAG_DescendantsFlowWithOptions(t *testing.T) {
+	d := NewDAG()
+	root, _ := d.AddVertex(0)
+	for i := 1; i <= 20; i++ {
+		id, _ := d.AddVertex(i)
+		_ = d.AddEdge(root, id)
+	}
+
+	var mu sync.Mutex
+	running, peak, calls := 0, 0, 0
+	flowCallback := func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		mu.Lock()
+		running++
+		calls++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil, nil
+	}
+
+	res, err := d.DescendantsFlowWithOptions(root, nil, flowCallback, FlowOptions{MaxConcurrency: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 20 {
+		t.Errorf("len(DescendantsFlowWithOptions()) = %d, want 20", len(res))
+	}
+	if calls != 21 {
+		t.Errorf("calls = %d, want 21", calls)
+	}
+	if peak > 3 {
+		t.Errorf("peak = %d, want at most 3", peak)
+	}
+
+	// unbounded
+	peak, calls = 0, 0
+	if _, err = d.DescendantsFlowWithOptions(root, nil, flowCallback, FlowOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 21 {
+		t.Errorf("calls = %d, want 21", calls)
+	}
+}
+
+func TestDAG_DescendantsFlowErrorMode(t *testing.T) {
+
+	//   0
+	// ┌─┴─┐
+	// 1   2
+	// │   │
+	// 3   4
+	// └─┬─┘
+	//   5
+	d := NewDAG()
+	for i := 0; i < 6; i++ {
+		_ = d.AddVertexByID(strconv.Itoa(i), i)
+	}
+	_ = d.AddEdges([][2]string{{"0", "1"}, {"0", "2"}, {"1", "3"}, {"2", "4"}, {"3", "5"}, {"4", "5"}})
+
+	err1 := errors.New("1 failed")
+	err4 := errors.New("4 failed")
+	var mu sync.Mutex
+	var calls []string
+	flowCallback := func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		mu.Lock()
+		calls = append(calls, id)
+		mu.Unlock()
+		switch id {
+		case "1":
+			return nil, err1
+		case "4":
+			return nil, err4
+		}
+		return id, nil
+	}
+
+	// ignore (default)
+	res, err := d.DescendantsFlowWithOptions("0", nil, flowCallback, FlowOptions{})
+	if err != nil || len(res) != 1 || len(calls) != 6 {
+		t.Errorf("DescendantsFlowWithOptions() = %v, %v with %d calls, want 1 result, no error, and 6 calls", res, err, len(calls))
+	}
+
+	// fail fast
+	calls = nil
+	res, err = d.DescendantsFlowWithOptions("0", nil, flowCallback, FlowOptions{ErrorMode: FlowErrorsFailFast})
+	if err != err1 && err != err4 {
+		t.Errorf("DescendantsFlowWithOptions() = %v, want %v or %v", err, err1, err4)
+	}
+	if len(res) != 0 {
+		t.Errorf("len(DescendantsFlowWithOptions()) = %d, want 0", len(res))
+	}
+	for _, id := range calls {
+		if id == "5" {
+			t.Errorf("DescendantsFlowWithOptions() called 5 after a parent failed")
+		}
+	}
+
+	// collect all
+	calls = nil
+	res, err = d.DescendantsFlowWithOptions("0", nil, flowCallback, FlowOptions{ErrorMode: FlowErrorsCollect})
+	if !errors.Is(err, err1) || !errors.Is(err, err4) {
+		t.Errorf("DescendantsFlowWithOptions() = %v, want %v and %v", err, err1, err4)
+	}
+	if errs, ok := err.(FlowErrors); !ok || len(errs) != 2 {
+		t.Errorf("DescendantsFlowWithOptions() = %#v, want FlowErrors of length 2", err)
+	}
+	if len(res) != 1 || res[0].ID != "5" {
+		t.Errorf("DescendantsFlowWithOptions() = %v, want the result of 5", res)
+	}
+	if len(calls) != 6 {
+		t.Errorf("len(calls) = %d, want 6", len(calls))
+	}
+}
+
+func TestDAG_LayeredFlow(t *testing.T) {
+
+	// a   b
+	// │   │
+	// │   c
+	// └─┬─┘
+	//   d
+	d := NewDAG()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		_ = d.AddVertexByID(id, id)
+	}
+	_ = d.AddEdges([][2]string{{"a", "d"}, {"b", "c"}, {"c", "d"}})
+	_ = d.SetEdgeLabel("c", "d", "label")
+
+	// a is slow, thus c (only depending on b) would start before a finished
+	// within DescendantsFlow
+	var mu sync.Mutex
+	var events []string
+	flowCallback := func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		mu.Lock()
+		events = append(events, "start "+id)
+		mu.Unlock()
+		if id == "a" {
+			time.Sleep(20 * time.Millisecond)
+		}
+		result := id
+		for _, r := range parentResults {
+			result += "(" + r.Result.(string) + ")"
+		}
+		mu.Lock()
+		events = append(events, "end "+id)
+		mu.Unlock()
+		return result, nil
+	}
+
+	res, err := d.LayeredFlow(flowCallback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].ID != "d" || res[0].Result != "d(a)(c(b))" {
+		t.Errorf("LayeredFlow() = %v, want the single result d(a)(c(b))", res)
+	}
+
+	// no callback of a level starts before all callbacks of previous levels ended
+	levels, _ := d.TopologicalLevels()
+	depths := make(map[string]int)
+	for depth, level := range levels {
+		for _, id := range level {
+			depths[id] = depth
+		}
+	}
+	ended := make(map[int]int)
+	for _, event := range events {
+		parts := strings.Fields(event)
+		depth := depths[parts[1]]
+		if parts[0] == "end" {
+			ended[depth]++
+			continue
+		}
+		for previous := 0; previous < depth; previous++ {
+			if ended[previous] != len(levels[previous]) {
+				t.Errorf("LayeredFlow() started %s before level %d ended: %v", parts[1], previous, events)
+			}
+		}
+	}
+
+	// parent results carry edge attributes
+	var label interface{}
+	labelCallback := func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		for _, r := range parentResults {
+			if r.ID == "c" {
+				label = r.EdgeLabel
+			}
+		}
+		return id, nil
+	}
+	if _, err = d.LayeredFlowWithOptions(labelCallback, FlowOptions{MaxConcurrency: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if label != "label" {
+		t.Errorf("EdgeLabel = %v, want label", label)
+	}
+
+	// fail fast stops before the next level
+	errB := errors.New("b failed")
+	var calls []string
+	failingCallback := func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		mu.Lock()
+		calls = append(calls, id)
+		mu.Unlock()
+		if id == "b" {
+			return nil, errB
+		}
+		return id, nil
+	}
+	res, err = d.LayeredFlowWithOptions(failingCallback, FlowOptions{ErrorMode: FlowErrorsFailFast})
+	if err != errB || len(res) != 0 || len(calls) != 2 {
+		t.Errorf("LayeredFlowWithOptions() = %v, %v with calls %v, want no result, %v, and 2 calls", res, err, calls, errB)
+	}
+
+	// collect runs all levels and returns the errors as FlowErrors
+	calls = nil
+	res, err = d.LayeredFlowWithOptions(failingCallback, FlowOptions{ErrorMode: FlowErrorsCollect})
+	if errs, ok := err.(FlowErrors); !ok || len(errs) != 1 || !errors.Is(err, errB) {
+		t.Errorf("LayeredFlowWithOptions() = %#v, want FlowErrors holding %v", err, errB)
+	}
+	if len(res) != 1 || len(calls) != 4 {
+		t.Errorf("LayeredFlowWithOptions() = %v with calls %v, want 1 result and 4 calls", res, calls)
+	}
+}
+
 func largeAux(d *DAG, level int, branches int, parent iVertex) (int, int) {
 	var vertexCount int
 	var edgeCount int
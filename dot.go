@@ -0,0 +1,64 @@
+package dag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dotReplacer escapes characters that would otherwise break quoted DOT ids.
+var dotReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+)
+
+// DOTOptions is the configuration for DOT.
+type DOTOptions struct {
+
+	// Clustered groups the vertices by their depth (see TopologicalLevels).
+	// That is, the vertices of each level are wrapped in a "subgraph cluster_i"
+	// with "rank=same", such that Graphviz lays out the levels as tiers.
+	Clustered bool
+}
+
+// DOT returns a textual representation of the graph in the Graphviz DOT
+// language. Vertices are rendered as "id" [label="label"] where id is the id
+// of the vertex and label the escaped label of the vertex (see
+// Options.StringFunc). Edges are rendered as "src" -> "dst".
+//
+// Vertices and edges are sorted by id, such that the output is stable.
+func (d *DAG) DOT(options DOTOptions) string {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	var sb strings.Builder
+	sb.WriteString("digraph {\n")
+
+	ids := vertexIDs(d.vertexIds)
+	if options.Clustered {
+		for i, level := range d.topologicalLevels() {
+			sb.WriteString(fmt.Sprintf("    subgraph cluster_%d {\n", i))
+			sb.WriteString("        rank=same;\n")
+			for _, id := range level {
+				sb.WriteString("    " + d.dotVertex(id))
+			}
+			sb.WriteString("    }\n")
+		}
+	} else {
+		for _, id := range ids {
+			sb.WriteString(d.dotVertex(id))
+		}
+	}
+	for _, id := range ids {
+		children, _ := d.getChildren(id)
+		for _, childID := range vertexIDs(children) {
+			sb.WriteString(fmt.Sprintf("    \"%s\" -> \"%s\";\n", dotReplacer.Replace(id), dotReplacer.Replace(childID)))
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// dotVertex returns the (indented) DOT statement of the vertex with the id id.
+func (d *DAG) dotVertex(id string) string {
+	return fmt.Sprintf("    \"%s\" [label=\"%s\"];\n", dotReplacer.Replace(id), dotReplacer.Replace(d.vertexString(id)))
+}
@@ -0,0 +1,58 @@
+package dag
+
+import "testing"
+
+func TestDAG_DOT(t *testing.T) {
+	dag := NewDAG()
+	_ = dag.AddVertexByID("4", testLabel(`say "hi"`))
+	_ = dag.AddVertexByID("3", testLabel("v3"))
+	_ = dag.AddVertexByID("2", testLabel("v2"))
+	_ = dag.AddVertexByID("1", testLabel("v1"))
+	_ = dag.AddEdge("1", "3")
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("2", "4")
+	_ = dag.AddEdge("3", "4")
+
+	expected := `digraph {
+    "1" [label="v1"];
+    "2" [label="v2"];
+    "3" [label="v3"];
+    "4" [label="say \"hi\""];
+    "1" -> "2";
+    "1" -> "3";
+    "2" -> "4";
+    "3" -> "4";
+}
+`
+	if actual := dag.DOT(DOTOptions{}); actual != expected {
+		t.Errorf("DOT() = %s, want %s", actual, expected)
+	}
+
+	expected = `digraph {
+    subgraph cluster_0 {
+        rank=same;
+        "1" [label="v1"];
+    }
+    subgraph cluster_1 {
+        rank=same;
+        "2" [label="v2"];
+        "3" [label="v3"];
+    }
+    subgraph cluster_2 {
+        rank=same;
+        "4" [label="say \"hi\""];
+    }
+    "1" -> "2";
+    "1" -> "3";
+    "2" -> "4";
+    "3" -> "4";
+}
+`
+	if actual := dag.DOT(DOTOptions{Clustered: true}); actual != expected {
+		t.Errorf("DOT(Clustered) = %s, want %s", actual, expected)
+	}
+
+	if actual := NewDAG().DOT(DOTOptions{Clustered: true}); actual != "digraph {\n}\n" {
+		t.Errorf("DOT() = %s, want %s", actual, "digraph {\n}\n")
+	}
+}
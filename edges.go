@@ -0,0 +1,191 @@
+package dag
+
+// defaultEdgeWeight is the weight of edges without an explicit weight.
+const defaultEdgeWeight = 1.0
+
+// AddWeightedEdge adds an edge between srcID and dstID with the given weight.
+// AddWeightedEdge returns an error, if srcID or dstID are empty strings or
+// unknown, if the edge already exists, or if the new edge would create a loop.
+func (d *DAG) AddWeightedEdge(srcID, dstID string, weight float64) error {
+
+	d.muDAG.Lock()
+	defer d.unlockAndEmit()
+
+	if err := d.addEdge(srcID, dstID); err != nil {
+		return err
+	}
+	srcHash := d.hashVertex(d.vertexIds[srcID])
+	dstHash := d.hashVertex(d.vertexIds[dstID])
+	if _, exists := d.edgeWeights[srcHash]; !exists {
+		d.edgeWeights[srcHash] = make(map[interface{}]float64)
+	}
+	d.edgeWeights[srcHash][dstHash] = weight
+	return nil
+}
+
+// GetEdgeWeight returns the weight of the edge between srcID and dstID. Edges
+// added without an explicit weight have a weight of 1. GetEdgeWeight returns an
+// error, if srcID or dstID are empty, unknown, or the same, or if there is no
+// edge between srcID and dstID.
+func (d *DAG) GetEdgeWeight(srcID, dstID string) (float64, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	srcHash, dstHash, err := d.saneEdge(srcID, dstID)
+	if err != nil {
+		return 0, err
+	}
+	return d.edgeWeight(srcHash, dstHash), nil
+}
+
+func (d *DAG) edgeWeight(srcHash, dstHash interface{}) float64 {
+	if weight, exists := d.edgeWeights[srcHash][dstHash]; exists {
+		return weight
+	}
+	return defaultEdgeWeight
+}
+
+// saneEdge checks that there is an edge between srcID and dstID and returns
+// the hashes of both vertices.
+func (d *DAG) saneEdge(srcID, dstID string) (srcHash, dstHash interface{}, err error) {
+	if err = d.saneID(srcID); err != nil {
+		return
+	}
+	if err = d.saneID(dstID); err != nil {
+		return
+	}
+	if srcID == dstID {
+		err = SrcDstEqualError{srcID, dstID}
+		return
+	}
+	srcHash = d.hashVertex(d.vertexIds[srcID])
+	dstHash = d.hashVertex(d.vertexIds[dstID])
+	if !d.isEdge(srcHash, dstHash) {
+		err = EdgeUnknownError{srcID, dstID}
+	}
+	return
+}
+
+// SetEdgeLabel attaches the given label to the edge between srcID and dstID
+// (replacing any previous label). SetEdgeLabel returns an error, if srcID or
+// dstID are empty, unknown, or the same, or if there is no edge between srcID
+// and dstID.
+func (d *DAG) SetEdgeLabel(srcID, dstID string, label interface{}) error {
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+
+	if d.frozen {
+		return FrozenError{}
+	}
+
+	srcHash, dstHash, err := d.saneEdge(srcID, dstID)
+	if err != nil {
+		return err
+	}
+	if _, exists := d.edgeLabels[srcHash]; !exists {
+		d.edgeLabels[srcHash] = make(map[interface{}]interface{})
+	}
+	d.edgeLabels[srcHash][dstHash] = label
+	return nil
+}
+
+// GetEdgeLabel returns the label of the edge between srcID and dstID or nil,
+// if no label has been set. GetEdgeLabel returns an error, if srcID or dstID
+// are empty, unknown, or the same, or if there is no edge between srcID and
+// dstID.
+func (d *DAG) GetEdgeLabel(srcID, dstID string) (interface{}, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	srcHash, dstHash, err := d.saneEdge(srcID, dstID)
+	if err != nil {
+		return nil, err
+	}
+	return d.edgeLabels[srcHash][dstHash], nil
+}
+
+// deleteEdgeAttributes deletes the weight and the label of the edge between
+// srcHash and dstHash.
+func (d *DAG) deleteEdgeAttributes(srcHash, dstHash interface{}) {
+	delete(d.edgeWeights[srcHash], dstHash)
+	if len(d.edgeWeights[srcHash]) == 0 {
+		delete(d.edgeWeights, srcHash)
+	}
+	delete(d.edgeLabels[srcHash], dstHash)
+	if len(d.edgeLabels[srcHash]) == 0 {
+		delete(d.edgeLabels, srcHash)
+	}
+}
+
+// deleteVertexEdgeAttributes deletes the weights and the labels of all in- and
+// outbound edges of vHash.
+func (d *DAG) deleteVertexEdgeAttributes(vHash interface{}) {
+	for srcHash := range d.edgeWeights {
+		d.deleteEdgeAttributes(srcHash, vHash)
+	}
+	for srcHash := range d.edgeLabels {
+		d.deleteEdgeAttributes(srcHash, vHash)
+	}
+	delete(d.edgeWeights, vHash)
+	delete(d.edgeLabels, vHash)
+}
+
+// copyEdgeAttributes copies the weights and labels of all edges between
+// vertices within newHashes to the respective edges of newDAG. newHashes maps
+// the hashes of vertices of d to the hashes of the corresponding vertices of
+// newDAG. Note, labels are copied shallowly.
+func (d *DAG) copyEdgeAttributes(newDAG *DAG, newHashes map[interface{}]interface{}) {
+	for srcHash, weights := range d.edgeWeights {
+		newSrcHash, exists := newHashes[srcHash]
+		if !exists {
+			continue
+		}
+		for dstHash, weight := range weights {
+			if newDstHash, exists := newHashes[dstHash]; exists {
+				if _, exists := newDAG.edgeWeights[newSrcHash]; !exists {
+					newDAG.edgeWeights[newSrcHash] = make(map[interface{}]float64)
+				}
+				newDAG.edgeWeights[newSrcHash][newDstHash] = weight
+			}
+		}
+	}
+	for srcHash, labels := range d.edgeLabels {
+		newSrcHash, exists := newHashes[srcHash]
+		if !exists {
+			continue
+		}
+		for dstHash, label := range labels {
+			if newDstHash, exists := newHashes[dstHash]; exists {
+				if _, exists := newDAG.edgeLabels[newSrcHash]; !exists {
+					newDAG.edgeLabels[newSrcHash] = make(map[interface{}]interface{})
+				}
+				newDAG.edgeLabels[newSrcHash][newDstHash] = label
+			}
+		}
+	}
+}
+
+// rehashEdgeAttributes replaces oldHash by newHash in the weights and labels of
+// all edges.
+func (d *DAG) rehashEdgeAttributes(oldHash, newHash interface{}) {
+	if weights, exists := d.edgeWeights[oldHash]; exists {
+		d.edgeWeights[newHash] = weights
+		delete(d.edgeWeights, oldHash)
+	}
+	for _, weights := range d.edgeWeights {
+		if weight, exists := weights[oldHash]; exists {
+			weights[newHash] = weight
+			delete(weights, oldHash)
+		}
+	}
+	if labels, exists := d.edgeLabels[oldHash]; exists {
+		d.edgeLabels[newHash] = labels
+		delete(d.edgeLabels, oldHash)
+	}
+	for _, labels := range d.edgeLabels {
+		if label, exists := labels[oldHash]; exists {
+			labels[newHash] = label
+			delete(labels, oldHash)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package dag
+
+import "testing"
+
+func TestDAG_AddWeightedEdge(t *testing.T) {
+	dag := NewDAG()
+	v1, _ := dag.AddVertex("1")
+	v2, _ := dag.AddVertex("2")
+	v3, _ := dag.AddVertex("3")
+
+	if err := dag.AddWeightedEdge(v1, v2, 2.5); err != nil {
+		t.Fatal(err)
+	}
+	if err := dag.AddEdge(v2, v3); err != nil {
+		t.Fatal(err)
+	}
+	if weight, _ := dag.GetEdgeWeight(v1, v2); weight != 2.5 {
+		t.Errorf("GetEdgeWeight(v1, v2) = %v, want %v", weight, 2.5)
+	}
+	if weight, _ := dag.GetEdgeWeight(v2, v3); weight != 1 {
+		t.Errorf("GetEdgeWeight(v2, v3) = %v, want %v", weight, 1)
+	}
+
+	// duplicate
+	errDuplicate := dag.AddWeightedEdge(v1, v2, 3)
+	if _, ok := errDuplicate.(EdgeDuplicateError); !ok {
+		t.Errorf("AddWeightedEdge(v1, v2, 3) expected EdgeDuplicateError, got %T", errDuplicate)
+	}
+	if weight, _ := dag.GetEdgeWeight(v1, v2); weight != 2.5 {
+		t.Errorf("GetEdgeWeight(v1, v2) = %v, want %v", weight, 2.5)
+	}
+
+	// loop
+	errLoop := dag.AddWeightedEdge(v3, v1, 1)
+	if _, ok := errLoop.(EdgeLoopError); !ok {
+		t.Errorf("AddWeightedEdge(v3, v1, 1) expected EdgeLoopError, got %T", errLoop)
+	}
+
+	// re-adding a deleted edge without weight resets the weight
+	_ = dag.DeleteEdge(v1, v2)
+	_ = dag.AddEdge(v1, v2)
+	if weight, _ := dag.GetEdgeWeight(v1, v2); weight != 1 {
+		t.Errorf("GetEdgeWeight(v1, v2) = %v, want %v", weight, 1)
+	}
+
+	// deleting a vertex deletes the weights of its edges
+	_ = dag.AddWeightedEdge(v1, v3, 4)
+	_ = dag.DeleteVertex(v3)
+	if len(dag.edgeWeights[dag.hashVertex("1")]) != 0 {
+		t.Errorf("len(edgeWeights[v1]) = %d, want 0", len(dag.edgeWeights[dag.hashVertex("1")]))
+	}
+}
+
+func TestDAG_GetEdgeWeight(t *testing.T) {
+	dag := NewDAG()
+	v1, _ := dag.AddVertex("1")
+	v2, _ := dag.AddVertex("2")
+
+	// unknown edge
+	_, errUnknownEdge := dag.GetEdgeWeight(v1, v2)
+	if _, ok := errUnknownEdge.(EdgeUnknownError); !ok {
+		t.Errorf("GetEdgeWeight(v1, v2) expected EdgeUnknownError, got %T", errUnknownEdge)
+	}
+
+	// nil
+	_, errNil := dag.GetEdgeWeight("", v2)
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("GetEdgeWeight(\"\", v2) expected IDEmptyError, got %T", errNil)
+	}
+
+	// unknown
+	_, errUnknown := dag.GetEdgeWeight(v1, "foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("GetEdgeWeight(v1, \"foo\") expected IDUnknownError, got %T", errUnknown)
+	}
+
+	// same
+	_, errSame := dag.GetEdgeWeight(v1, v1)
+	if _, ok := errSame.(SrcDstEqualError); !ok {
+		t.Errorf("GetEdgeWeight(v1, v1) expected SrcDstEqualError, got %T", errSame)
+	}
+}
+
+func TestDAG_SetEdgeLabel(t *testing.T) {
+	dag := NewDAG()
+	v1, _ := dag.AddVertex("1")
+	v2, _ := dag.AddVertex("2")
+	v3, _ := dag.AddVertex("3")
+	_ = dag.AddEdge(v1, v2)
+
+	if label, _ := dag.GetEdgeLabel(v1, v2); label != nil {
+		t.Errorf("GetEdgeLabel(v1, v2) = %v, want nil", label)
+	}
+	if err := dag.SetEdgeLabel(v1, v2, "on success"); err != nil {
+		t.Fatal(err)
+	}
+
+	// labels survive unrelated edge additions and deletions
+	_ = dag.AddEdge(v1, v3)
+	_ = dag.AddEdge(v2, v3)
+	_ = dag.DeleteEdge(v1, v3)
+	if label, _ := dag.GetEdgeLabel(v1, v2); label != "on success" {
+		t.Errorf("GetEdgeLabel(v1, v2) = %v, want %v", label, "on success")
+	}
+
+	// unknown edge
+	errUnknownEdge := dag.SetEdgeLabel(v1, v3, "foo")
+	if _, ok := errUnknownEdge.(EdgeUnknownError); !ok {
+		t.Errorf("SetEdgeLabel(v1, v3, \"foo\") expected EdgeUnknownError, got %T", errUnknownEdge)
+	}
+	_, errUnknownEdge = dag.GetEdgeLabel(v1, v3)
+	if _, ok := errUnknownEdge.(EdgeUnknownError); !ok {
+		t.Errorf("GetEdgeLabel(v1, v3) expected EdgeUnknownError, got %T", errUnknownEdge)
+	}
+
+	// nil
+	errNil := dag.SetEdgeLabel("", v2, "foo")
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("SetEdgeLabel(\"\", v2, \"foo\") expected IDEmptyError, got %T", errNil)
+	}
+
+	// re-adding a deleted edge doesn't restore its label
+	_ = dag.DeleteEdge(v1, v2)
+	_ = dag.AddEdge(v1, v2)
+	if label, _ := dag.GetEdgeLabel(v1, v2); label != nil {
+		t.Errorf("GetEdgeLabel(v1, v2) = %v, want nil", label)
+	}
+
+	// deleting a vertex deletes the labels of its edges
+	_ = dag.SetEdgeLabel(v1, v2, "foo")
+	_ = dag.SetEdgeLabel(v2, v3, "bar")
+	_ = dag.DeleteVertex(v2)
+	if len(dag.edgeLabels) != 0 {
+		t.Errorf("len(edgeLabels) = %d, want 0", len(dag.edgeLabels))
+	}
+}
@@ -0,0 +1,85 @@
+package dag
+
+// ChangeType describes the kind of a structural change of a DAG.
+type ChangeType int
+
+const (
+	// VertexAdded signals that a vertex has been added.
+	VertexAdded ChangeType = iota
+
+	// VertexDeleted signals that a vertex (and all its edges) has been deleted.
+	VertexDeleted
+
+	// EdgeAdded signals that an edge has been added.
+	EdgeAdded
+
+	// EdgeDeleted signals that an edge has been deleted.
+	EdgeDeleted
+)
+
+// ChangeEvent describes a structural change of a DAG. For vertex events, ID
+// holds the id of the vertex. For edge events, SrcID and DstID hold the ids
+// of the source and destination vertices of the edge.
+type ChangeEvent struct {
+	Type  ChangeType
+	ID    string
+	SrcID string
+	DstID string
+}
+
+// OnChange registers fn to be called for each vertex and each edge that is
+// added to or deleted from the graph, no matter which method did so (e.g.
+// AddVertex, AddEdges, Prune, ContractEdge, or ReduceTransitively). Multiple
+// functions may be registered and are called in the order of their
+// registration. Methods that fail (and thus leave the graph unchanged) don't
+// emit any events.
+//
+// Note, fn is called after the lock of the DAG has been released. Thus, fn
+// may call methods of the DAG (incl. mutating ones). However, by the time fn
+// is called, the DAG may have been modified again (concurrently). Also note,
+// when a vertex is deleted, only a single VertexDeleted event is emitted (i.e.
+// no EdgeDeleted events for the attached edges).
+func (d *DAG) OnChange(fn func(event ChangeEvent)) {
+	d.muListeners.Lock()
+	defer d.muListeners.Unlock()
+	d.listeners = append(d.listeners, fn)
+}
+
+// record queues the given event to be emitted as soon as the lock of the DAG
+// is released (see unlockAndEmit). Events are only queued, if there are
+// registered functions. record must be called with the write lock held.
+func (d *DAG) record(event ChangeEvent) {
+	d.muListeners.RLock()
+	listening := len(d.listeners) > 0
+	d.muListeners.RUnlock()
+	if listening {
+		d.events = append(d.events, event)
+	}
+}
+
+// discardEvents drops all queued events (e.g. after a failed operation has
+// been rolled back).
+func (d *DAG) discardEvents() {
+	d.events = nil
+}
+
+// unlockAndEmit releases the write lock of the DAG and emits all queued events
+// afterwards.
+func (d *DAG) unlockAndEmit() {
+	events := d.events
+	d.events = nil
+	d.muDAG.Unlock()
+	for _, event := range events {
+		d.emit(event)
+	}
+}
+
+// emit calls all registered functions with the given event.
+func (d *DAG) emit(event ChangeEvent) {
+	d.muListeners.RLock()
+	listeners := d.listeners
+	d.muListeners.RUnlock()
+	for _, fn := range listeners {
+		fn(event)
+	}
+}
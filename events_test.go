@@ -0,0 +1,113 @@
+package dag
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestDAG_OnChange(t *testing.T) {
+	dag := NewDAG()
+	var events []ChangeEvent
+	dag.OnChange(func(event ChangeEvent) {
+		events = append(events, event)
+	})
+
+	_ = dag.AddVertexByID("1", "v1")
+	_ = dag.AddVertexByID("2", "v2")
+	_ = dag.AddVertexByID("2", "v2") // fails, no event
+	id, _ := dag.AddVertex("v3")
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("2", "1") // fails, no event
+	_ = dag.AddEdge("2", id)
+	_ = dag.DeleteEdge("1", "2")
+	_ = dag.DeleteVertex(id)
+	_ = dag.DeleteVertex(id) // fails, no event
+
+	expected := []ChangeEvent{
+		{Type: VertexAdded, ID: "1"},
+		{Type: VertexAdded, ID: "2"},
+		{Type: VertexAdded, ID: id},
+		{Type: EdgeAdded, SrcID: "1", DstID: "2"},
+		{Type: EdgeAdded, SrcID: "2", DstID: id},
+		{Type: EdgeDeleted, SrcID: "1", DstID: "2"},
+		{Type: VertexDeleted, ID: id},
+	}
+	if diff := deep.Equal(events, expected); diff != nil {
+		t.Errorf("events = %v, want %v", events, expected)
+	}
+}
+
+func TestDAG_OnChangeReentrant(t *testing.T) {
+	dag := NewDAG()
+	var orders []int
+
+	// calling the DAG from within the callback doesn't deadlock
+	dag.OnChange(func(event ChangeEvent) {
+		orders = append(orders, dag.GetOrder())
+		if event.Type == VertexAdded && event.ID == "1" {
+			_ = dag.AddVertexByID("2", "v2")
+		}
+	})
+	_ = dag.AddVertexByID("1", "v1")
+
+	expected := []int{1, 2}
+	if diff := deep.Equal(orders, expected); diff != nil {
+		t.Errorf("orders = %v, want %v", orders, expected)
+	}
+}
+
+func TestDAG_OnChangeBulk(t *testing.T) {
+	dag := NewDAG()
+	var events []ChangeEvent
+	dag.OnChange(func(event ChangeEvent) {
+		events = append(events, event)
+	})
+
+	ids, _ := dag.AddVertices([]interface{}{"v1", "v2", "v3", "v4"})
+	if len(events) != 4 {
+		t.Errorf("len(events) = %d, want 4", len(events))
+	}
+	events = nil
+
+	// failing bulk operations are rolled back and emit nothing
+	_ = dag.AddEdges([][2]string{{ids[0], ids[1]}, {ids[1], ids[0]}})
+	_, _ = dag.AddVertices([]interface{}{"v5", "v1"})
+	if len(events) != 0 {
+		t.Errorf("events = %v, want none", events)
+	}
+
+	// a -> b -> c and a -> c
+	_ = dag.AddEdges([][2]string{{ids[0], ids[1]}, {ids[1], ids[2]}, {ids[0], ids[2]}})
+	dag.ReduceTransitively()
+	_ = dag.DeleteVertices([]string{ids[3]})
+	expected := []ChangeEvent{
+		{Type: EdgeAdded, SrcID: ids[0], DstID: ids[1]},
+		{Type: EdgeAdded, SrcID: ids[1], DstID: ids[2]},
+		{Type: EdgeAdded, SrcID: ids[0], DstID: ids[2]},
+		{Type: EdgeDeleted, SrcID: ids[0], DstID: ids[2]},
+		{Type: VertexDeleted, ID: ids[3]},
+	}
+	if diff := deep.Equal(events, expected); diff != nil {
+		t.Errorf("events = %v, want %v", events, expected)
+	}
+
+	// contracting an edge deletes dst and rewires its edges
+	events = nil
+	_ = dag.ContractEdge(ids[0], ids[1])
+	expected = []ChangeEvent{
+		{Type: VertexDeleted, ID: ids[1]},
+		{Type: EdgeAdded, SrcID: ids[0], DstID: ids[2]},
+	}
+	if diff := deep.Equal(events, expected); diff != nil {
+		t.Errorf("events = %v, want %v", events, expected)
+	}
+
+	// pruning deletes all other vertices
+	events = nil
+	_, _ = dag.Prune([]string{ids[2]})
+	expected = []ChangeEvent{{Type: VertexDeleted, ID: ids[0]}}
+	if diff := deep.Equal(events, expected); diff != nil {
+		t.Errorf("events = %v, want %v", events, expected)
+	}
+}
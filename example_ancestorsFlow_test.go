@@ -0,0 +1,59 @@
+package dag_test
+
+import (
+	"fmt"
+	"github.com/heimdalr/dag"
+	"sort"
+)
+
+func ExampleDAG_AncestorsFlow() {
+	// Initialize a new graph.
+	d := dag.NewDAG()
+
+	// Init vertices.
+	v0, _ := d.AddVertex(0)
+	v1, _ := d.AddVertex(1)
+	v2, _ := d.AddVertex(2)
+	v3, _ := d.AddVertex(3)
+	v4, _ := d.AddVertex(4)
+
+	// Add the above vertices and connect them.
+	_ = d.AddEdge(v0, v1)
+	_ = d.AddEdge(v0, v3)
+	_ = d.AddEdge(v1, v2)
+	_ = d.AddEdge(v2, v4)
+	_ = d.AddEdge(v3, v4)
+
+	//   0
+	// ┌─┴─┐
+	// 1   │
+	// │   3
+	// 2   │
+	// └─┬─┘
+	//   4
+
+	// The callback function adds its own value (ID) to the sum of children results.
+	flowCallback := func(d *dag.DAG, id string, childResults []dag.FlowResult) (interface{}, error) {
+
+		v, _ := d.GetVertex(id)
+		result, _ := v.(int)
+		var children []int
+		for _, r := range childResults {
+			c, _ := d.GetVertex(r.ID)
+			children = append(children, c.(int))
+			result += r.Result.(int)
+		}
+		sort.Ints(children)
+		fmt.Printf("%v based on: %+v returns: %d\n", v, children, result)
+		return result, nil
+	}
+
+	_, _ = d.AncestorsFlow(v4, nil, flowCallback)
+
+	// Unordered output:
+	// 4 based on: [] returns: 4
+	// 2 based on: [4] returns: 6
+	// 3 based on: [4] returns: 7
+	// 1 based on: [2] returns: 7
+	// 0 based on: [1 3] returns: 14
+}
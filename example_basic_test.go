@@ -10,14 +10,22 @@ type foobar struct {
 	b string
 }
 
+// String makes foobar implement fmt.Stringer, such that it is used as label
+// (e.g. by DAG.String).
+func (fb foobar) String() string {
+	return fmt.Sprintf("%s %s", fb.a, fb.b)
+}
+
 func Example() {
 
 	// initialize a new graph
 	d := dag.NewDAG()
 
-	// init three vertices
-	v1, _ := d.AddVertex(1)
-	v2, _ := d.AddVertex(2)
+	// init three vertices (vertices not implementing fmt.Stringer are labeled
+	// by their ids)
+	v1, v2 := "1", "2"
+	_ = d.AddVertexByID(v1, 1)
+	_ = d.AddVertexByID(v2, 2)
 	v3, _ := d.AddVertex(foobar{a: "foo", b: "bar"})
 
 	// add the above vertices and connect them with two edges
@@ -32,8 +40,8 @@ func Example() {
 	// Vertices:
 	//   1
 	//   2
-	//   {foo bar}
+	//   foo bar
 	// Edges:
 	//   1 -> 2
-	//   1 -> {foo bar}
+	//   1 -> foo bar
 }
@@ -0,0 +1,53 @@
+package dag_test
+
+import (
+	"fmt"
+	"github.com/heimdalr/dag"
+)
+
+func ExampleDAG_DescendantsFlowV() {
+	// Initialize a new graph.
+	d := dag.NewDAG()
+
+	// Init vertices.
+	v0, _ := d.AddVertex(0)
+	v1, _ := d.AddVertex(1)
+	v2, _ := d.AddVertex(2)
+	v3, _ := d.AddVertex(3)
+	v4, _ := d.AddVertex(4)
+
+	// Add the above vertices and connect them.
+	_ = d.AddEdge(v0, v1)
+	_ = d.AddEdge(v0, v3)
+	_ = d.AddEdge(v1, v2)
+	_ = d.AddEdge(v2, v4)
+	_ = d.AddEdge(v3, v4)
+
+	//   0
+	// ┌─┴─┐
+	// 1   │
+	// │   3
+	// 2   │
+	// └─┬─┘
+	//   4
+
+	// The callback function adds its own value to the sum of parent results. In
+	// contrast to DescendantsFlow, the value is passed to the callback.
+	flowCallback := func(d *dag.DAG, id string, value interface{}, parentResults []dag.FlowResult) (interface{}, error) {
+		result := value.(int)
+		for _, r := range parentResults {
+			result += r.Result.(int)
+		}
+		fmt.Printf("%v returns: %d\n", value, result)
+		return result, nil
+	}
+
+	_, _ = d.DescendantsFlowV(v0, nil, flowCallback)
+
+	// Unordered output:
+	// 0 returns: 0
+	// 1 returns: 1
+	// 3 returns: 3
+	// 2 returns: 3
+	// 4 returns: 10
+}
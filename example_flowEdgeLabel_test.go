@@ -0,0 +1,62 @@
+package dag_test
+
+import (
+	"fmt"
+	"github.com/heimdalr/dag"
+)
+
+func ExampleDAG_DescendantsFlowMulti_edgeLabel() {
+	// Initialize a new graph.
+	d := dag.NewDAG()
+
+	// Init vertices.
+	v2, _ := d.AddVertex(2)
+	v3, _ := d.AddVertex(3)
+	vSum, _ := d.AddVertex("sum")
+	vProduct, _ := d.AddVertex("product")
+
+	// Add the above vertices and connect them. The edge labels tell the
+	// receiving vertex how to combine the results of its parents.
+	_ = d.AddEdge(v2, vSum)
+	_ = d.AddEdge(v3, vSum)
+	_ = d.AddEdge(v2, vProduct)
+	_ = d.AddEdge(v3, vProduct)
+	_ = d.SetEdgeLabel(v2, vSum, "+")
+	_ = d.SetEdgeLabel(v3, vSum, "+")
+	_ = d.SetEdgeLabel(v2, vProduct, "*")
+	_ = d.SetEdgeLabel(v3, vProduct, "*")
+
+	// 2     3
+	// ├──┬──┤
+	// │  └──┼──┐
+	// sum   product
+
+	// The callback function returns the value of vertices without parents and
+	// otherwise either sums up or multiplies the results of the parents
+	// (depending on the labels of the edges the results have been passed over).
+	flowCallback := func(d *dag.DAG, id string, parentResults []dag.FlowResult) (interface{}, error) {
+		if len(parentResults) == 0 {
+			return d.GetVertex(id)
+		}
+		result := 0
+		if parentResults[0].EdgeLabel == "*" {
+			result = 1
+		}
+		for _, r := range parentResults {
+			switch r.EdgeLabel {
+			case "+":
+				result += r.Result.(int)
+			case "*":
+				result *= r.Result.(int)
+			}
+		}
+		return result, nil
+	}
+
+	res, _ := d.DescendantsFlowMulti([]string{v2, v3}, nil, flowCallback)
+	results := dag.ResultsByID(res)
+	fmt.Printf("sum: %v, product: %v\n", results[vSum], results[vProduct])
+
+	// Output:
+	// sum: 5, product: 6
+}
@@ -8,6 +8,7 @@ require (
 	github.com/emirpasic/gods v1.18.1
 	github.com/go-test/deep v1.1.0
 	github.com/google/uuid v1.3.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
-retract [v1.4.1, v1.4.11]
\ No newline at end of file
+retract [v1.4.1, v1.4.11]
@@ -0,0 +1,42 @@
+//go:build go1.23
+
+package dag
+
+import "iter"
+
+// Vertices returns an iterator over all vertices (i.e. pairs of id and value).
+// Unlike GetVertices, Vertices doesn't copy the vertices.
+//
+// Note, the graph is read-locked while iterating. The lock is released as soon
+// as the iteration finishes or is stopped (e.g. via break). Thus, the graph must
+// not be modified within the loop body.
+func (d *DAG) Vertices() iter.Seq2[string, interface{}] {
+	return func(yield func(string, interface{}) bool) {
+		d.muDAG.RLock()
+		defer d.muDAG.RUnlock()
+		for id, v := range d.vertexIds {
+			if !yield(id, v) {
+				return
+			}
+		}
+	}
+}
+
+// Edges returns an iterator over all edges (i.e. pairs of srcID and dstID).
+//
+// Note, the graph is read-locked while iterating. The lock is released as soon
+// as the iteration finishes or is stopped (e.g. via break). Thus, the graph must
+// not be modified within the loop body.
+func (d *DAG) Edges() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		d.muDAG.RLock()
+		defer d.muDAG.RUnlock()
+		for src, children := range d.outboundEdge {
+			for dst := range children {
+				if !yield(d.vertices[src], d.vertices[dst]) {
+					return
+				}
+			}
+		}
+	}
+}
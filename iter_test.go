@@ -0,0 +1,72 @@
+//go:build go1.23
+
+package dag
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDAG_Vertices(t *testing.T) {
+	dag := getTestWalkDAG()
+
+	vertices := make(map[string]interface{})
+	for id, v := range dag.Vertices() {
+		vertices[id] = v
+	}
+	if len(vertices) != 5 || vertices["3"] != "v3" {
+		t.Errorf("Vertices() = %v, want 5 vertices incl. 3: v3", vertices)
+	}
+
+	// break early and modify the graph afterwards
+	count := 0
+	for range dag.Vertices() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("count = %d, want 1", count)
+	}
+	done := make(chan error)
+	go func() { done <- dag.AddVertexByID("6", "v6") }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("AddVertexByID() blocked after breaking out of Vertices()")
+	}
+}
+
+func TestDAG_Edges(t *testing.T) {
+	dag := getTestWalkDAG()
+
+	edges := make(map[[2]string]bool)
+	for src, dst := range dag.Edges() {
+		edges[[2]string{src, dst}] = true
+	}
+	if len(edges) != 4 || !edges[[2]string{"2", "4"}] {
+		t.Errorf("Edges() = %v, want 4 edges incl. 2 -> 4", edges)
+	}
+
+	// break early and modify the graph afterwards
+	count := 0
+	for range dag.Edges() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("count = %d, want 1", count)
+	}
+	done := make(chan error)
+	go func() { done <- dag.AddEdge("1", "5") }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("AddEdge() blocked after breaking out of Edges()")
+	}
+}
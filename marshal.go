@@ -3,16 +3,32 @@ package dag
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 )
 
-// MarshalJSON returns the JSON encoding of DAG.
+// MarshalJSON returns the JSON encoding of DAG. The read lock of the DAG is
+// held during the entire serialization (see Snapshot).
 //
-// It traverses the DAG using the Depth-First-Search algorithm
-// and uses an internal structure to store vertices and edges.
+// The encoding is deterministic. That is, vertices are ordered by their ids
+// and edges are ordered by the ids of their source and destination vertices.
 func (d *DAG) MarshalJSON() ([]byte, error) {
-	mv := newMarshalVisitor(d)
-	d.DFSWalk(mv)
-	return json.Marshal(mv.storableDAG)
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return json.Marshal(d.toStorableDAG())
+}
+
+// Snapshot returns the JSON encoding of DAG (see MarshalJSON) reflecting a
+// single consistent state of the graph, even if the graph is concurrently
+// modified by other goroutines. That is, the read lock of the DAG is held for
+// the entire serialization (including the encoding of the values of the
+// vertices and the labels of the edges).
+//
+// Note, values referenced by the vertices (e.g. via pointers) are not
+// protected by the lock of the DAG. Modifying them concurrently is up to the
+// caller.
+func (d *DAG) Snapshot() ([]byte, error) {
+	return d.MarshalJSON()
 }
 
 // UnmarshalJSON is an informative method. See the UnmarshalJSON function below.
@@ -24,6 +40,9 @@ func (d *DAG) UnmarshalJSON(_ []byte) error {
 // It returns a new DAG defined by the vertices and edges of wd.
 // If the internal structure of data and wd do not match,
 // then deserialization will fail and return json error.
+// If data contains duplicate ids, edges between unknown vertices, or
+// edges that would create a loop, UnmarshalJSON fails with an error
+// wrapping the respective error of AddVertexByID or AddEdge.
 //
 // Because the vertex data passed in by the user is an interface{},
 // it does not indicate a specific structure, so it cannot be deserialized.
@@ -47,43 +66,162 @@ func UnmarshalJSON(data []byte, wd StorableDAG, options Options) (*DAG, error) {
 	if err != nil {
 		return nil, err
 	}
+	return fromStorableDAG(wd, options)
+}
+
+// UnmarshalJSONStream reads the JSON-encoded DAG (as written by MarshalJSON)
+// from r and returns a new DAG (with default options). In contrast to
+// UnmarshalJSON, the vertices and edges are added to the DAG as they are
+// decoded. Thus, there is no intermediate StorableDAG holding all vertices and
+// edges. newVertex must return a pointer to a new (empty) Vertexer to decode
+// a vertex into (e.g. `func() Vertexer { return &YourVertex{} }`).
+//
+// Note, the vertices must precede the edges in the input (as written by
+// MarshalJSON).
+func UnmarshalJSONStream(r io.Reader, newVertex func() Vertexer) (*DAG, error) {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	dag := NewDAG()
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch token {
+		case "vs":
+			err = decodeArray(dec, func() error {
+				v := newVertex()
+				if err := dec.Decode(v); err != nil {
+					return err
+				}
+				id, value := v.Vertex()
+				if err := dag.AddVertexByID(id, value); err != nil {
+					return fmt.Errorf("failed to add vertex '%s': %w", id, err)
+				}
+				return nil
+			})
+		case "es":
+			err = decodeArray(dec, func() error {
+				var e storableEdge
+				if err := dec.Decode(&e); err != nil {
+					return err
+				}
+				return addEdger(dag, e)
+			})
+		default:
+			var ignored json.RawMessage
+			err = dec.Decode(&ignored)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, err
+	}
+	return dag, nil
+}
+
+// decodeArray reads a JSON array from dec calling decodeElement for each
+// element.
+func decodeArray(dec *json.Decoder, decodeElement func() error) error {
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+	for dec.More() {
+		if err := decodeElement(); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// expectDelim reads the next token from dec and returns an error, if it isn't
+// the given delimiter.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected '%v' but got '%v'", delim, token)
+	}
+	return nil
+}
+
+// fromStorableDAG returns a new DAG with the given options defined by the
+// vertices and edges of wd.
+func fromStorableDAG(wd StorableDAG, options Options) (*DAG, error) {
 	dag := NewDAG()
 	dag.Options(options)
 	for _, v := range wd.Vertices() {
-		errVertex := dag.AddVertexByID(v.Vertex())
+		id, value := v.Vertex()
+		errVertex := dag.AddVertexByID(id, value)
 		if errVertex != nil {
-			return nil, errVertex
+			return nil, fmt.Errorf("failed to add vertex '%s': %w", id, errVertex)
 		}
 	}
 	for _, e := range wd.Edges() {
-		errEdge := dag.AddEdge(e.Edge())
-		if errEdge != nil {
+		if errEdge := addEdger(dag, e); errEdge != nil {
 			return nil, errEdge
 		}
 	}
 	return dag, nil
 }
 
-type marshalVisitor struct {
-	d *DAG
-	storableDAG
-}
+// addEdger adds the edge e to dag. If e is a WeightedEdger or LabeledEdger,
+// its weight and label are set as well (if any).
+func addEdger(dag *DAG, e Edger) error {
+	srcID, dstID := e.Edge()
 
-func newMarshalVisitor(d *DAG) *marshalVisitor {
-	return &marshalVisitor{d: d}
-}
+	var weight float64
+	var weighted bool
+	if we, ok := e.(WeightedEdger); ok {
+		weight, weighted = we.EdgeWeight()
+	}
 
-func (mv *marshalVisitor) Visit(v Vertexer) {
-	mv.StorableVertices = append(mv.StorableVertices, v)
+	var err error
+	if weighted {
+		err = dag.AddWeightedEdge(srcID, dstID, weight)
+	} else {
+		err = dag.AddEdge(srcID, dstID)
+	}
+	if le, ok := e.(LabeledEdger); ok && err == nil {
+		if label, ok := le.EdgeLabel(); ok {
+			err = dag.SetEdgeLabel(srcID, dstID, label)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add edge from '%s' to '%s': %w", srcID, dstID, err)
+	}
+	return nil
+}
 
-	srcID, _ := v.Vertex()
-	// Why not use Mutex here?
-	// Because at the time of Walk,
-	// the read lock has been used to protect the dag.
-	children, _ := mv.d.getChildren(srcID)
-	ids := vertexIDs(children)
-	for _, dstID := range ids {
-		e := storableEdge{SrcID: srcID, DstID: dstID}
-		mv.StorableEdges = append(mv.StorableEdges, e)
+// toStorableDAG returns the vertices and edges of the graph as storableDAG.
+// Vertices are ordered by their ids and edges by the ids of their source and
+// destination vertices. The edges carry their weights and labels (see
+// WeightedEdger and LabeledEdger).
+func (d *DAG) toStorableDAG() storableDAG {
+	sd := storableDAG{
+		StorableVertices: make([]Vertexer, 0, len(d.vertexIds)),
+		StorableEdges:    make([]Edger, 0, d.getSize()),
+	}
+	for _, srcID := range vertexIDs(d.vertexIds) {
+		sd.StorableVertices = append(sd.StorableVertices, storableVertex{WrappedID: srcID, Value: d.vertexIds[srcID]})
+		srcHash := d.hashVertex(d.vertexIds[srcID])
+		children, _ := d.getChildren(srcID)
+		for _, dstID := range vertexIDs(children) {
+			e := storableEdge{SrcID: srcID, DstID: dstID}
+			dstHash := d.hashVertex(d.vertexIds[dstID])
+			if weight, exists := d.edgeWeights[srcHash][dstHash]; exists {
+				e.Weight = &weight
+			}
+			e.Label = d.edgeLabels[srcHash][dstHash]
+			sd.StorableEdges = append(sd.StorableEdges, e)
+		}
 	}
+	return sd
 }
@@ -1,7 +1,12 @@
 package dag
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/go-test/deep"
@@ -18,15 +23,15 @@ func TestMarshalUnmarshalJSON(t *testing.T) {
 		},
 		{
 			dag:      getTestWalkDAG2(),
-			expected: `{"vs":[{"i":"1","v":"v1"},{"i":"3","v":"v3"},{"i":"5","v":"v5"},{"i":"2","v":"v2"},{"i":"4","v":"v4"}],"es":[{"s":"1","d":"3"},{"s":"3","d":"5"},{"s":"2","d":"3"},{"s":"4","d":"5"}]}`,
+			expected: `{"vs":[{"i":"1","v":"v1"},{"i":"2","v":"v2"},{"i":"3","v":"v3"},{"i":"4","v":"v4"},{"i":"5","v":"v5"}],"es":[{"s":"1","d":"3"},{"s":"2","d":"3"},{"s":"3","d":"5"},{"s":"4","d":"5"}]}`,
 		},
 		{
 			dag:      getTestWalkDAG3(),
-			expected: `{"vs":[{"i":"1","v":"v1"},{"i":"3","v":"v3"},{"i":"2","v":"v2"},{"i":"4","v":"v4"},{"i":"5","v":"v5"}],"es":[{"s":"1","d":"3"},{"s":"2","d":"3"},{"s":"4","d":"5"}]}`,
+			expected: `{"vs":[{"i":"1","v":"v1"},{"i":"2","v":"v2"},{"i":"3","v":"v3"},{"i":"4","v":"v4"},{"i":"5","v":"v5"}],"es":[{"s":"1","d":"3"},{"s":"2","d":"3"},{"s":"4","d":"5"}]}`,
 		},
 		{
 			dag:      getTestWalkDAG4(),
-			expected: `{"vs":[{"i":"1","v":"v1"},{"i":"2","v":"v2"},{"i":"3","v":"v3"},{"i":"5","v":"v5"},{"i":"4","v":"v4"}],"es":[{"s":"1","d":"2"},{"s":"2","d":"3"},{"s":"2","d":"4"},{"s":"3","d":"5"}]}`,
+			expected: `{"vs":[{"i":"1","v":"v1"},{"i":"2","v":"v2"},{"i":"3","v":"v3"},{"i":"4","v":"v4"},{"i":"5","v":"v5"}],"es":[{"s":"1","d":"2"},{"s":"2","d":"3"},{"s":"2","d":"4"},{"s":"3","d":"5"}]}`,
 		},
 	}
 
@@ -35,6 +40,129 @@ func TestMarshalUnmarshalJSON(t *testing.T) {
 	}
 }
 
+func TestMarshalJSONDeterministic(t *testing.T) {
+	d := NewDAG()
+	for i := 0; i < 50; i++ {
+		_ = d.AddVertexByID(fmt.Sprintf("%02d", i), i)
+	}
+	for i := 1; i < 50; i++ {
+		_ = d.AddEdge(fmt.Sprintf("%02d", i%7), fmt.Sprintf("%02d", i))
+	}
+
+	expected, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		data, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != string(expected) {
+			t.Fatalf("Marshal() = %s, want %s", data, expected)
+		}
+	}
+}
+
+func TestMarshalUnmarshalJSONEdgeAttributes(t *testing.T) {
+	d := NewDAG()
+	_ = d.AddVertexByID("1", "v1")
+	_ = d.AddVertexByID("2", "v2")
+	_ = d.AddVertexByID("3", "v3")
+	_ = d.AddWeightedEdge("1", "2", 2.5)
+	_ = d.AddEdge("2", "3")
+	_ = d.SetEdgeLabel("2", "3", "depends")
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"vs":[{"i":"1","v":"v1"},{"i":"2","v":"v2"},{"i":"3","v":"v3"}],"es":[{"s":"1","d":"2","w":2.5},{"s":"2","d":"3","l":"depends"}]}`
+	if string(data) != expected {
+		t.Errorf("Marshal() = %s, want %s", data, expected)
+	}
+
+	var wd testStorableDAG
+	dag, err := UnmarshalJSON(data, &wd, defaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weight, _ := dag.GetEdgeWeight("1", "2"); weight != 2.5 {
+		t.Errorf("GetEdgeWeight() = %v, want %v", weight, 2.5)
+	}
+	if weight, _ := dag.GetEdgeWeight("2", "3"); weight != 1 {
+		t.Errorf("GetEdgeWeight() = %v, want %v", weight, 1)
+	}
+	if label, _ := dag.GetEdgeLabel("2", "3"); label != "depends" {
+		t.Errorf("GetEdgeLabel() = %v, want %v", label, "depends")
+	}
+	if label, _ := dag.GetEdgeLabel("1", "2"); label != nil {
+		t.Errorf("GetEdgeLabel() = %v, want nil", label)
+	}
+}
+
+// testAttributedEdge is a user-defined edge carrying a weight and a label.
+type testAttributedEdge struct {
+	Src    string  `json:"s"`
+	Dst    string  `json:"d"`
+	Weight float64 `json:"w"`
+	Label  string  `json:"l"`
+}
+
+func (e testAttributedEdge) Edge() (srcID, dstID string) {
+	return e.Src, e.Dst
+}
+
+func (e testAttributedEdge) EdgeWeight() (float64, bool) {
+	return e.Weight, e.Weight != 0
+}
+
+func (e testAttributedEdge) EdgeLabel() (interface{}, bool) {
+	return e.Label, e.Label != ""
+}
+
+type testAttributedStorableDAG struct {
+	StorableVertices []testVertex         `json:"vs"`
+	StorableEdges    []testAttributedEdge `json:"es"`
+}
+
+func (g testAttributedStorableDAG) Vertices() []Vertexer {
+	l := make([]Vertexer, 0, len(g.StorableVertices))
+	for _, v := range g.StorableVertices {
+		l = append(l, v)
+	}
+	return l
+}
+
+func (g testAttributedStorableDAG) Edges() []Edger {
+	l := make([]Edger, 0, len(g.StorableEdges))
+	for _, e := range g.StorableEdges {
+		l = append(l, e)
+	}
+	return l
+}
+
+func TestUnmarshalJSONEdgerAttributes(t *testing.T) {
+	data := `{"vs":[{"i":"1","v":"v1"},{"i":"2","v":"v2"},{"i":"3","v":"v3"}],"es":[{"s":"1","d":"2","w":2.5},{"s":"2","d":"3","l":"depends"}]}`
+	var wd testAttributedStorableDAG
+	dag, err := UnmarshalJSON([]byte(data), &wd, defaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weight, _ := dag.GetEdgeWeight("1", "2"); weight != 2.5 {
+		t.Errorf("GetEdgeWeight() = %v, want %v", weight, 2.5)
+	}
+	if weight, _ := dag.GetEdgeWeight("2", "3"); weight != 1 {
+		t.Errorf("GetEdgeWeight() = %v, want %v", weight, 1)
+	}
+	if label, _ := dag.GetEdgeLabel("2", "3"); label != "depends" {
+		t.Errorf("GetEdgeLabel() = %v, want %v", label, "depends")
+	}
+	if label, _ := dag.GetEdgeLabel("1", "2"); label != nil {
+		t.Errorf("GetEdgeLabel() = %v, want nil", label)
+	}
+}
+
 func testMarshalUnmarshalJSON(t *testing.T, d *DAG, expected string) {
 	data, err := json.Marshal(d)
 	if err != nil {
@@ -57,7 +185,142 @@ func testMarshalUnmarshalJSON(t *testing.T, d *DAG, expected string) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if deep.Equal(d, dag) != nil {
+	if !d.Equal(dag) {
 		t.Errorf("UnmarshalJSON() = %v, want %v", dag.String(), d.String())
 	}
 }
+
+func TestUnmarshalJSONErrors(t *testing.T) {
+
+	// duplicate id
+	data := `{"vs":[{"i":"1","v":"v1"},{"i":"1","v":"v2"}],"es":[]}`
+	var wd testStorableDAG
+	dag, err := UnmarshalJSON([]byte(data), &wd, defaultOptions())
+	if dag != nil {
+		t.Errorf("UnmarshalJSON() = %v, want nil", dag)
+	}
+	var errID IDDuplicateError
+	if !errors.As(err, &errID) {
+		t.Errorf("UnmarshalJSON() expected IDDuplicateError, got %T", err)
+	}
+
+	// cycle
+	data = `{"vs":[{"i":"1","v":"v1"},{"i":"2","v":"v2"},{"i":"3","v":"v3"}],"es":[{"s":"1","d":"2"},{"s":"2","d":"3"},{"s":"3","d":"1"}]}`
+	wd = testStorableDAG{}
+	dag, err = UnmarshalJSON([]byte(data), &wd, defaultOptions())
+	if dag != nil {
+		t.Errorf("UnmarshalJSON() = %v, want nil", dag)
+	}
+	var errLoop EdgeLoopError
+	if !errors.As(err, &errLoop) {
+		t.Errorf("UnmarshalJSON() expected EdgeLoopError, got %T", err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "'3' to '1'") {
+		t.Errorf("UnmarshalJSON() = %v, expected the error to name the edge", err)
+	}
+
+	// unknown vertex
+	data = `{"vs":[{"i":"1","v":"v1"}],"es":[{"s":"1","d":"2"}]}`
+	wd = testStorableDAG{}
+	_, err = UnmarshalJSON([]byte(data), &wd, defaultOptions())
+	var errUnknown IDUnknownError
+	if !errors.As(err, &errUnknown) {
+		t.Errorf("UnmarshalJSON() expected IDUnknownError, got %T", err)
+	}
+}
+
+func TestSnapshotConcurrent(t *testing.T) {
+	d := NewDAG()
+	_ = d.AddVertexByID("0", "v0")
+
+	// churn: add vertices and edges and delete some of them again
+	done := make(chan struct{})
+	churned := make(chan struct{})
+	go func() {
+		defer close(churned)
+		for i := 1; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			id := strconv.Itoa(i)
+			_ = d.AddVertexByID(id, "v"+id)
+			_ = d.AddEdge(strconv.Itoa(i/2), id)
+			if i%3 == 0 {
+				_ = d.DeleteVertex(strconv.Itoa(i - 1))
+			}
+		}
+	}()
+
+	for i := 0; i < 50; i++ {
+		data, err := d.Snapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var wd testStorableDAG
+		restored, err := UnmarshalJSON(data, &wd, defaultOptions())
+		if err != nil {
+			t.Fatalf("UnmarshalJSON() = %v, want nil", err)
+		}
+		if len(wd.StorableVertices) != restored.GetOrder() || len(wd.StorableEdges) != restored.GetSize() {
+			t.Errorf("Snapshot() has %d vertices and %d edges, restored %d and %d", len(wd.StorableVertices), len(wd.StorableEdges), restored.GetOrder(), restored.GetSize())
+		}
+	}
+	close(done)
+	<-churned
+}
+
+func TestUnmarshalJSONStream(t *testing.T) {
+	d := NewDAG()
+	for i := 0; i < 2000; i++ {
+		_ = d.AddVertexByID(strconv.Itoa(i), fmt.Sprintf("v%d", i))
+	}
+	for i := 1; i < 2000; i++ {
+		_ = d.AddEdge(strconv.Itoa(i/2), strconv.Itoa(i))
+		_ = d.AddEdge(strconv.Itoa(i/3), strconv.Itoa(i))
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newVertex := func() Vertexer { return &testVertex{} }
+	streamed, err := UnmarshalJSONStream(bytes.NewReader(data), newVertex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wd testStorableDAG
+	unmarshalled, err := UnmarshalJSON(data, &wd, defaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !streamed.Equal(unmarshalled) || !streamed.Equal(d) {
+		t.Errorf("UnmarshalJSONStream() differs from UnmarshalJSON()")
+	}
+
+	// unknown keys are ignored
+	data = []byte(`{"foo":[1,{"bar":2}],"vs":[{"i":"1","v":"v1"},{"i":"2","v":"v2"}],"es":[{"s":"1","d":"2"}]}`)
+	streamed, err = UnmarshalJSONStream(bytes.NewReader(data), newVertex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order, size := streamed.GetOrder(), streamed.GetSize(); order != 2 || size != 1 {
+		t.Errorf("UnmarshalJSONStream() has %d vertices and %d edges, want 2 and 1", order, size)
+	}
+
+	// cycle
+	data = []byte(`{"vs":[{"i":"1","v":"v1"},{"i":"2","v":"v2"}],"es":[{"s":"1","d":"2"},{"s":"2","d":"1"}]}`)
+	_, err = UnmarshalJSONStream(bytes.NewReader(data), newVertex)
+	var errLoop EdgeLoopError
+	if !errors.As(err, &errLoop) {
+		t.Errorf("UnmarshalJSONStream() expected EdgeLoopError, got %T", err)
+	}
+
+	// malformed
+	for _, data := range []string{`[]`, `{"vs":{}}`, `{"vs":[{"i":"1","v":"v1"}]`, `{"vs":[{"i":1}]}`} {
+		if _, err = UnmarshalJSONStream(strings.NewReader(data), newVertex); err == nil {
+			t.Errorf("UnmarshalJSONStream(%s) = nil, want error", data)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package dag
+
+// AdjacencyMatrix returns the adjacency matrix of the graph as well as the ids
+// indexing its rows and columns. The ids are sorted and the cell [i][j] is true
+// iff there is an edge from ids[i] to ids[j].
+func (d *DAG) AdjacencyMatrix() ([][]bool, []string, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	ids := vertexIDs(d.vertexIds)
+	indices := make(map[string]int, len(ids))
+	for i, id := range ids {
+		indices[id] = i
+	}
+
+	matrix := make([][]bool, len(ids))
+	for i, id := range ids {
+		matrix[i] = make([]bool, len(ids))
+		for child := range d.outboundEdge[d.hashVertex(d.vertexIds[id])] {
+			matrix[i][indices[d.vertices[child]]] = true
+		}
+	}
+	return matrix, ids, nil
+}
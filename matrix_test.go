@@ -0,0 +1,57 @@
+package dag
+
+import "testing"
+
+func TestDAG_AdjacencyMatrix(t *testing.T) {
+	dag := getTestWalkDAG()
+
+	matrix, ids, err := dag.AdjacencyMatrix()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedIDs := []string{"1", "2", "3", "4", "5"}
+	if !equal(ids, expectedIDs) {
+		t.Errorf("AdjacencyMatrix() ids = %v, want %v", ids, expectedIDs)
+	}
+	if len(matrix) != len(ids) {
+		t.Fatalf("len(AdjacencyMatrix()) = %d, want %d", len(matrix), len(ids))
+	}
+
+	edges := 0
+	for _, row := range matrix {
+		if len(row) != len(ids) {
+			t.Fatalf("len(row) = %d, want %d", len(row), len(ids))
+		}
+		for _, cell := range row {
+			if cell {
+				edges++
+			}
+		}
+	}
+	if edges != dag.GetSize() {
+		t.Errorf("AdjacencyMatrix() contains %d edges, want %d", edges, dag.GetSize())
+	}
+
+	cases := []struct {
+		i, j     int
+		expected bool
+	}{
+		{0, 1, true},  // 1 -> 2
+		{1, 3, true},  // 2 -> 4
+		{3, 4, true},  // 4 -> 5
+		{1, 0, false}, // 2 -> 1
+		{0, 2, false}, // 1 -> 3
+		{2, 2, false}, // 3 -> 3
+	}
+	for _, c := range cases {
+		if matrix[c.i][c.j] != c.expected {
+			t.Errorf("AdjacencyMatrix()[%d][%d] = %v, want %v", c.i, c.j, matrix[c.i][c.j], c.expected)
+		}
+	}
+
+	// empty
+	matrix, ids, _ = NewDAG().AdjacencyMatrix()
+	if len(matrix) != 0 || len(ids) != 0 {
+		t.Errorf("AdjacencyMatrix() = %v, %v, want [], []", matrix, ids)
+	}
+}
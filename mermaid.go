@@ -0,0 +1,42 @@
+package dag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// mermaidLabelReplacer escapes characters that would otherwise break the
+// Mermaid syntax using Mermaid's entity codes.
+var mermaidLabelReplacer = strings.NewReplacer(
+	`#`, `#35;`,
+	`"`, `#quot;`,
+	`[`, `#91;`,
+	`]`, `#93;`,
+)
+
+// Mermaid returns a textual representation of the graph in the Mermaid
+// flowchart syntax (i.e. "graph TD"). Vertices are rendered as id["label"]
+// where id is the id of the vertex and label the escaped label of the vertex
+// (see Options.StringFunc). Edges are rendered as src --> dst.
+//
+// Vertices and edges are sorted by id, such that the output is stable.
+func (d *DAG) Mermaid() string {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	var sb strings.Builder
+	sb.WriteString("graph TD\n")
+
+	ids := vertexIDs(d.vertexIds)
+	for _, id := range ids {
+		label := mermaidLabelReplacer.Replace(d.vertexString(id))
+		sb.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", id, label))
+	}
+	for _, id := range ids {
+		children, _ := d.getChildren(id)
+		for _, childID := range vertexIDs(children) {
+			sb.WriteString(fmt.Sprintf("    %s --> %s\n", id, childID))
+		}
+	}
+	return sb.String()
+}
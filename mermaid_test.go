@@ -0,0 +1,33 @@
+package dag
+
+import "testing"
+
+func TestDAG_Mermaid(t *testing.T) {
+	dag := NewDAG()
+	_ = dag.AddVertexByID("4", testLabel(`say "hi"`))
+	_ = dag.AddVertexByID("3", testLabel("[v3]"))
+	_ = dag.AddVertexByID("2", testLabel("v2"))
+	_ = dag.AddVertexByID("1", testLabel("v1"))
+	_ = dag.AddEdge("1", "3")
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("2", "4")
+	_ = dag.AddEdge("3", "4")
+
+	expected := `graph TD
+    1["v1"]
+    2["v2"]
+    3["#91;v3#93;"]
+    4["say #quot;hi#quot;"]
+    1 --> 2
+    1 --> 3
+    2 --> 4
+    3 --> 4
+`
+	if actual := dag.Mermaid(); actual != expected {
+		t.Errorf("Mermaid() = %s, want %s", actual, expected)
+	}
+
+	if actual := NewDAG().Mermaid(); actual != "graph TD\n" {
+		t.Errorf("Mermaid() = %s, want %s", actual, "graph TD\n")
+	}
+}
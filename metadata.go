@@ -0,0 +1,39 @@
+package dag
+
+// SetMetadata attaches the given value under the given key to the vertex with
+// the id id (replacing any previous value of key). Metadata is kept separately
+// from the value of the vertex and is deleted along with the vertex. Note,
+// metadata is neither part of copies (e.g. via Copy) nor of the serialized
+// graph (e.g. via MarshalJSON). SetMetadata returns an error, if id is empty
+// or unknown.
+func (d *DAG) SetMetadata(id string, key string, value interface{}) error {
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+
+	if d.frozen {
+		return FrozenError{}
+	}
+
+	if err := d.saneID(id); err != nil {
+		return err
+	}
+	vHash := d.hashVertex(d.vertexIds[id])
+	if _, exists := d.metadata[vHash]; !exists {
+		d.metadata[vHash] = make(map[string]interface{})
+	}
+	d.metadata[vHash][key] = value
+	return nil
+}
+
+// GetMetadata returns the value attached under the given key to the vertex
+// with the id id or nil, if no such value has been set (see SetMetadata).
+// GetMetadata returns an error, if id is empty or unknown.
+func (d *DAG) GetMetadata(id, key string) (interface{}, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if err := d.saneID(id); err != nil {
+		return nil, err
+	}
+	return d.metadata[d.hashVertex(d.vertexIds[id])][key], nil
+}
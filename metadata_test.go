@@ -0,0 +1,59 @@
+package dag
+
+import "testing"
+
+func TestDAG_Metadata(t *testing.T) {
+	dag := NewDAG()
+	for _, id := range []string{"1", "2", "3"} {
+		_ = dag.AddVertexByID(id, "v"+id)
+	}
+	_ = dag.AddEdge("1", "2")
+
+	if err := dag.SetMetadata("2", "priority", 5); err != nil {
+		t.Fatal(err)
+	}
+	_ = dag.SetMetadata("2", "retries", 3)
+	if value, _ := dag.GetMetadata("2", "priority"); value != 5 {
+		t.Errorf("GetMetadata(2, priority) = %v, want 5", value)
+	}
+	if value, _ := dag.GetMetadata("1", "priority"); value != nil {
+		t.Errorf("GetMetadata(1, priority) = %v, want nil", value)
+	}
+
+	// metadata is not part of the value and survives edge changes
+	if v, _ := dag.GetVertex("2"); v != "v2" {
+		t.Errorf("GetVertex(2) = %v, want v2", v)
+	}
+	_ = dag.DeleteEdge("1", "2")
+	_ = dag.AddEdge("3", "2")
+	_ = dag.ReParent("2", "1")
+	if value, _ := dag.GetMetadata("2", "retries"); value != 3 {
+		t.Errorf("GetMetadata(2, retries) = %v, want 3", value)
+	}
+
+	// metadata survives replacing the value
+	_ = dag.ReplaceVertexValue("2", "new")
+	if value, _ := dag.GetMetadata("2", "priority"); value != 5 {
+		t.Errorf("GetMetadata(2, priority) = %v, want 5", value)
+	}
+
+	// metadata is removed with the vertex
+	_ = dag.DeleteVertex("2")
+	if len(dag.metadata) != 0 {
+		t.Errorf("len(metadata) = %d, want 0", len(dag.metadata))
+	}
+	_ = dag.AddVertexByID("2", "new")
+	if value, _ := dag.GetMetadata("2", "priority"); value != nil {
+		t.Errorf("GetMetadata(2, priority) = %v, want nil", value)
+	}
+
+	// unknown
+	_, errUnknown := dag.GetMetadata("foo", "priority")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("GetMetadata(foo, priority) expected IDUnknownError, got %T", errUnknown)
+	}
+	errUnknown = dag.SetMetadata("foo", "priority", 1)
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("SetMetadata(foo, priority) expected IDUnknownError, got %T", errUnknown)
+	}
+}
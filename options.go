@@ -1,18 +1,75 @@
 package dag
 
+import (
+	"fmt"
+	"reflect"
+)
+
 // Options is the configuration for the DAG.
 type Options struct {
 	// VertexHashFunc is the function that calculates the hash value of a vertex.
 	// This can be useful when the vertex contains not comparable types such as maps.
-	// If VertexHashFunc is nil, the defaultVertexHashFunc is used.
+	// If VertexHashFunc is nil, the defaultVertexHashFunc is used, which uses
+	// the vertex itself as hash. Thus, vertices that are not comparable require a
+	// VertexHashFunc. Otherwise, AddVertex returns a VertexNotComparableError.
 	VertexHashFunc func(v interface{}) interface{}
+
+	// VertexIDFunc is the function that derives the id of a vertex added via
+	// AddVertex. If VertexIDFunc is nil, the id of vertices implementing
+	// IDInterface is used, and a new UUID is generated otherwise.
+	// If VertexIDFunc returns an id that is already part of the graph,
+	// AddVertex returns an IDDuplicateError.
+	VertexIDFunc func(v interface{}) string
+
+	// StringFunc is the function that renders the label of a vertex in the
+	// textual representations of the graph (i.e. String, Mermaid, and DOT). If
+	// StringFunc is nil, the String method of the vertex is used, if it
+	// implements fmt.Stringer, and the id of the vertex otherwise.
+	StringFunc func(id string, v interface{}) string
+
+	// Identity controls how AddVertex (and AddVertexByID) decides whether two
+	// vertices are the same. With ByHash (the default), vertices are the same, if
+	// VertexHashFunc returns the same hash for them. With ByPointer, pointers
+	// (as well as maps, slices, channels, and functions) are the same only if
+	// they refer to the same memory, no matter what VertexHashFunc returns.
+	// Thus, ByPointer allows to add vertices that look equal but are separate
+	// allocations. All other values are still identified by VertexHashFunc.
+	Identity Identity
+
+	// MaxCacheEntries limits the number of entries of the ancestors- and of the
+	// descendants-cache (each). If a cache exceeds this limit, its least
+	// recently used entries are evicted. A value of 0 or less means unlimited.
+	//
+	// Note, evicted entries are recomputed on demand. Thus, a small limit may
+	// slow down queries (like GetDescendants) considerably.
+	MaxCacheEntries int
+
+	// DisableCaching disables the ancestors- and descendants-cache entirely.
+	// Ancestors and descendants are then computed from scratch for each query
+	// and mutations (like AddEdge) skip all cache bookkeeping. This trades
+	// query speed for cheaper mutations (e.g. for write-heavy workloads).
+	DisableCaching bool
 }
 
+// Identity is the type of Options.Identity.
+type Identity int
+
+const (
+	// ByHash identifies vertices by the hash returned by VertexHashFunc.
+	ByHash Identity = iota
+
+	// ByPointer identifies pointer-like vertices by their address.
+	ByPointer
+)
+
 // Options sets the options for the DAG.
 // Options must be called before any other method of the DAG is called.
 func (d *DAG) Options(options Options) {
 	d.muDAG.Lock()
 	defer d.muDAG.Unlock()
+	if options.VertexHashFunc == nil {
+		options.VertexHashFunc = defaultVertexHashFunc
+	}
 	d.options = options
 }
 
@@ -25,3 +82,69 @@ func defaultOptions() Options {
 func defaultVertexHashFunc(v interface{}) interface{} {
 	return v
 }
+
+// hashable returns true, if the hash h may be used as map key (i.e. if h
+// doesn't contain non-comparable values like maps, slices, or functions).
+func hashable(h interface{}) bool {
+	return h == nil || comparableValue(reflect.ValueOf(h))
+}
+
+// comparableValue returns true, if v is comparable. Unlike for
+// reflect.Type.Comparable, this includes the dynamic values of (nested)
+// interfaces.
+func comparableValue(v reflect.Value) bool {
+	if !v.Type().Comparable() {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		return v.IsNil() || comparableValue(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !comparableValue(v.Field(i)) {
+				return false
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !comparableValue(v.Index(i)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// pointerHash is the hash of pointer-like values if vertices are identified
+// ByPointer (see Options.Identity).
+type pointerHash struct {
+	t   reflect.Type
+	p   uintptr
+	len int
+}
+
+// hashPointer returns the pointerHash of v and true, if v is pointer-like.
+// Otherwise, it returns nil and false.
+func hashPointer(v interface{}) (interface{}, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return pointerHash{t: rv.Type(), p: rv.Pointer()}, true
+	case reflect.Slice:
+		return pointerHash{t: rv.Type(), p: rv.Pointer(), len: rv.Len()}, true
+	}
+	return nil, false
+}
+
+// vertexString returns the label of the vertex with the given id as of
+// Options.StringFunc.
+func (d *DAG) vertexString(id string) string {
+	v := d.vertexIds[id]
+	if d.options.StringFunc != nil {
+		return d.options.StringFunc(id, v)
+	}
+	if s, ok := v.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return id
+}
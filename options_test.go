@@ -2,6 +2,9 @@ package dag
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -184,10 +187,10 @@ func TestOverrideVertexHashFunOption(t *testing.T) {
 	dag.FlushCaches()
 	dag.DescendantsWalker(vertexId1) // nolint:errcheck
 
-	mv := newMarshalVisitor(dag)
-	dag.DFSWalk(mv)
-	dag.BFSWalk(mv)
-	dag.OrderedWalk(mv)
+	sv := &storableVisitor{}
+	dag.DFSWalk(sv)
+	dag.BFSWalk(sv)
+	dag.OrderedWalk(sv)
 
 	_, err = dag.MarshalJSON()
 	if err != nil {
@@ -205,3 +208,316 @@ func TestOverrideVertexHashFunOption(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestGetVertexIDNonComparable(t *testing.T) {
+	dag := NewDAG()
+	dag.Options(Options{
+		VertexHashFunc: func(v interface{}) interface{} {
+			return v.(testNonComparableVertexType).ID
+		}})
+
+	v := testNonComparableVertexType{
+		ID:                 "1",
+		NotComparableField: map[string]string{"not": "comparable"},
+	}
+	id, _ := dag.AddVertex(v)
+
+	// an equal but distinct value is found via the VertexHashFunc
+	lookup := testNonComparableVertexType{
+		ID:                 "1",
+		NotComparableField: map[string]string{"not": "comparable"},
+	}
+	if got, exists := dag.GetVertexID(lookup); !exists || got != id {
+		t.Errorf("GetVertexID() = %s, %v, want %s, true", got, exists, id)
+	}
+
+	unknown := testNonComparableVertexType{ID: "2"}
+	if got, exists := dag.GetVertexID(unknown); exists {
+		t.Errorf("GetVertexID() = %s, %v, want \"\", false", got, exists)
+	}
+}
+
+func TestEdgeVNonComparable(t *testing.T) {
+	dag := NewDAG()
+	dag.Options(Options{
+		VertexHashFunc: func(v interface{}) interface{} {
+			return v.(testNonComparableVertexType).ID
+		}})
+
+	v1 := testNonComparableVertexType{ID: "1", NotComparableField: map[string]string{"a": "1"}}
+	v2 := testNonComparableVertexType{ID: "2", NotComparableField: map[string]string{"b": "2"}}
+	v3 := testNonComparableVertexType{ID: "3"}
+	id1, _ := dag.AddVertex(v1)
+	id2, _ := dag.AddVertex(v2)
+
+	if err := dag.AddEdgeV(v1, v2); err != nil {
+		t.Fatal(err)
+	}
+	if isEdge, _ := dag.IsEdge(id1, id2); !isEdge {
+		t.Errorf("IsEdge(%s, %s) = false, want true", id1, id2)
+	}
+
+	// values are resolved via the VertexHashFunc (i.e. a distinct but equal
+	// value works as well)
+	lookup := testNonComparableVertexType{ID: "1", NotComparableField: map[string]string{"a": "1"}}
+	if isEdge, err := dag.IsEdgeV(lookup, v2); !isEdge || err != nil {
+		t.Errorf("IsEdgeV(v1, v2) = %v, %v, want true, nil", isEdge, err)
+	}
+	if isEdge, err := dag.IsEdgeV(v2, v1); isEdge || err != nil {
+		t.Errorf("IsEdgeV(v2, v1) = %v, %v, want false, nil", isEdge, err)
+	}
+
+	// errors
+	_, errEqual := dag.IsEdgeV(v1, v1)
+	if _, ok := errEqual.(SrcDstEqualError); !ok {
+		t.Errorf("IsEdgeV(v1, v1) expected SrcDstEqualError, got %T", errEqual)
+	}
+	_, errUnknown := dag.IsEdgeV(v1, v3)
+	if _, ok := errUnknown.(VertexUnknownError); !ok {
+		t.Errorf("IsEdgeV(v1, v3) expected VertexUnknownError, got %T", errUnknown)
+	}
+	errNil := dag.AddEdgeV(nil, v1)
+	if _, ok := errNil.(VertexNilError); !ok {
+		t.Errorf("AddEdgeV(nil, v1) expected VertexNilError, got %T", errNil)
+	}
+	errDuplicate := dag.AddEdgeV(v1, v2)
+	if _, ok := errDuplicate.(EdgeDuplicateError); !ok {
+		t.Errorf("AddEdgeV(v1, v2) expected EdgeDuplicateError, got %T", errDuplicate)
+	}
+	errLoop := dag.AddEdgeV(v2, v1)
+	if _, ok := errLoop.(EdgeLoopError); !ok {
+		t.Errorf("AddEdgeV(v2, v1) expected EdgeLoopError, got %T", errLoop)
+	}
+
+	if err := dag.DeleteEdgeV(v1, v2); err != nil {
+		t.Fatal(err)
+	}
+	if size := dag.GetSize(); size != 0 {
+		t.Errorf("GetSize() = %d, want 0", size)
+	}
+	errUnknownEdge := dag.DeleteEdgeV(v1, v2)
+	if _, ok := errUnknownEdge.(EdgeUnknownError); !ok {
+		t.Errorf("DeleteEdgeV(v1, v2) expected EdgeUnknownError, got %T", errUnknownEdge)
+	}
+	errUnknown = dag.DeleteEdgeV(v3, v2)
+	if _, ok := errUnknown.(VertexUnknownError); !ok {
+		t.Errorf("DeleteEdgeV(v3, v2) expected VertexUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDefaultVertexHashFuncNonComparable(t *testing.T) {
+	dag := NewDAG()
+
+	v := testNonComparableVertexType{ID: "1", NotComparableField: map[string]string{"a": "1"}}
+	_, err := dag.AddVertex(v)
+	if _, ok := err.(VertexNotComparableError); !ok {
+		t.Errorf("AddVertex() expected VertexNotComparableError, got %T", err)
+	}
+	if order := dag.GetOrder(); order != 0 {
+		t.Errorf("GetOrder() = %d, want 0", order)
+	}
+	if _, exists := dag.GetVertexID(v); exists {
+		t.Errorf("GetVertexID() = _, true, want _, false")
+	}
+	if _, _, err := dag.AddEdgeAndVertices("2", v); !errors.Is(err, ErrVertexNotComparable) {
+		t.Errorf("AddEdgeAndVertices() = %v, want %v", err, ErrVertexNotComparable)
+	}
+	if order := dag.GetOrder(); order != 0 {
+		t.Errorf("GetOrder() = %d, want 0", order)
+	}
+
+	id, _ := dag.AddVertex("3")
+	if err := dag.ReplaceVertexValue(id, v); !errors.Is(err, ErrVertexNotComparable) {
+		t.Errorf("ReplaceVertexValue() = %v, want %v", err, ErrVertexNotComparable)
+	}
+	if _, err := dag.IsEdgeV("3", v); !errors.Is(err, ErrVertexNotComparable) {
+		t.Errorf("IsEdgeV() = %v, want %v", err, ErrVertexNotComparable)
+	}
+}
+
+func TestHashable(t *testing.T) {
+	type nested struct {
+		v interface{}
+	}
+	cases := []struct {
+		h        interface{}
+		expected bool
+	}{
+		{nil, true},
+		{"1", true},
+		{[2]int{1, 2}, true},
+		{nested{"1"}, true},
+		{nested{}, true},
+		{[]int{1}, false},
+		{map[string]int{}, false},
+		{nested{[]int{1}}, false},
+		{[1]interface{}{nested{map[string]int{}}}, false},
+	}
+	for _, c := range cases {
+		if actual := hashable(c.h); actual != c.expected {
+			t.Errorf("hashable(%#v) = %v, want %v", c.h, actual, c.expected)
+		}
+	}
+}
+
+func TestVertexIDFuncOption(t *testing.T) {
+	dag := NewDAG()
+	counter := 0
+	dag.Options(Options{
+		VertexIDFunc: func(v interface{}) string {
+			counter++
+			return fmt.Sprintf("v%d", counter)
+		}})
+
+	// the VertexHashFunc defaults to defaultVertexHashFunc
+	for i, v := range []interface{}{"foo", iVertex{42}, 3.14} {
+		id, err := dag.AddVertex(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("v%d", i+1); id != want {
+			t.Errorf("AddVertex(%v) = %s, want %s", v, id, want)
+		}
+	}
+	if v, _ := dag.GetVertex("v2"); v != (iVertex{42}) {
+		t.Errorf("GetVertex(v2) = %v, want %v", v, iVertex{42})
+	}
+
+	// collision
+	counter = 0
+	_, errDuplicate := dag.AddVertex("bar")
+	if _, ok := errDuplicate.(IDDuplicateError); !ok {
+		t.Errorf("AddVertex(bar) expected IDDuplicateError, got %T", errDuplicate)
+	}
+	if order := dag.GetOrder(); order != 3 {
+		t.Errorf("GetOrder() = %d, want 3", order)
+	}
+}
+
+func TestStringFuncOption(t *testing.T) {
+	dag := NewDAG()
+	dag.Options(Options{
+		StringFunc: func(id string, v interface{}) string {
+			return fmt.Sprintf("<%s:%v>", id, v.(iVertex).value)
+		}})
+	_, _ = dag.AddVertex(iVertex{1})
+	_, _ = dag.AddVertex(iVertex{2})
+	_ = dag.AddEdge("1", "2")
+
+	s := dag.String()
+	for _, want := range []string{"  <1:1>\n", "  <2:2>\n", "  <1:1> -> <2:2>\n"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("String() = %q, want it to contain %q", s, want)
+		}
+	}
+	m := dag.Mermaid()
+	if want := `1["<1:1>"]`; !strings.Contains(m, want) {
+		t.Errorf("Mermaid() = %q, want it to contain %q", m, want)
+	}
+}
+
+func TestIdentityOption(t *testing.T) {
+	type testType struct{ value string }
+	derefHash := func(v interface{}) interface{} {
+		if p, ok := v.(*testType); ok {
+			return *p
+		}
+		return v
+	}
+
+	// ByHash: equal-looking pointers are the same vertex as of VertexHashFunc
+	byHash := NewDAG()
+	byHash.Options(Options{VertexHashFunc: derefHash})
+	v1, v2 := &testType{"1"}, &testType{"1"}
+	if _, err := byHash.AddVertex(v1); err != nil {
+		t.Fatal(err)
+	}
+	_, errDuplicate := byHash.AddVertex(v2)
+	if _, ok := errDuplicate.(VertexDuplicateError); !ok {
+		t.Errorf("AddVertex() expected VertexDuplicateError, got %T", errDuplicate)
+	}
+
+	// ByPointer: only the very same pointer is the same vertex
+	byPointer := NewDAG()
+	byPointer.Options(Options{VertexHashFunc: derefHash, Identity: ByPointer})
+	id1, err := byPointer.AddVertex(v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id2, err := byPointer.AddVertex(v2)
+	if err != nil {
+		t.Fatalf("AddVertex() = %v, want nil", err)
+	}
+	_, errDuplicate = byPointer.AddVertex(v1)
+	if _, ok := errDuplicate.(VertexDuplicateError); !ok {
+		t.Errorf("AddVertex() expected VertexDuplicateError, got %T", errDuplicate)
+	}
+	if err := byPointer.AddEdge(id1, id2); err != nil {
+		t.Fatal(err)
+	}
+	if id, exists := byPointer.GetVertexID(v2); !exists || id != id2 {
+		t.Errorf("GetVertexID() = %s, %v, want %s, true", id, exists, id2)
+	}
+	if id, exists := byPointer.GetVertexID(&testType{"1"}); exists {
+		t.Errorf("GetVertexID() = %s, %v, want \"\", false", id, exists)
+	}
+
+	// maps are identified by reference, too
+	m1, m2 := map[string]int{"a": 1}, map[string]int{"a": 1}
+	if _, err := byPointer.AddVertex(m1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := byPointer.AddVertex(m2); err != nil {
+		t.Errorf("AddVertex() = %v, want nil", err)
+	}
+
+	// non-pointer values are still identified by VertexHashFunc
+	if _, err := byPointer.AddVertex(testType{"1"}); err != nil {
+		t.Fatal(err)
+	}
+	_, errDuplicate = byPointer.AddVertex(testType{"1"})
+	if _, ok := errDuplicate.(VertexDuplicateError); !ok {
+		t.Errorf("AddVertex() expected VertexDuplicateError, got %T", errDuplicate)
+	}
+
+	if err := byPointer.DeleteVertex(id1); err != nil {
+		t.Fatal(err)
+	}
+	if order := byPointer.GetOrder(); order != 4 {
+		t.Errorf("GetOrder() = %d, want 4", order)
+	}
+}
+
+// testLabel is a vertex implementing fmt.Stringer.
+type testLabel string
+
+func (l testLabel) String() string {
+	return string(l)
+}
+
+func TestStringFuncDefault(t *testing.T) {
+	dag := NewDAG()
+	_ = dag.AddVertexByID("1", testLabel("v1"))
+	_ = dag.AddVertexByID("2", 2)
+	_ = dag.AddEdge("1", "2")
+
+	// Stringers are labeled by their String method and all others by their id
+	s := dag.String()
+	for _, want := range []string{"  v1\n", "  2\n", "  v1 -> 2\n"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("String() = %q, want it to contain %q", s, want)
+		}
+	}
+	_ = dag.ReplaceVertexValue("2", "v2")
+	if m := dag.Mermaid(); !strings.Contains(m, `2["2"]`) {
+		t.Errorf("Mermaid() = %q, want it to contain %q", m, `2["2"]`)
+	}
+}
+
+type storableVisitor struct {
+	storableDAG
+}
+
+func (sv *storableVisitor) Visit(v Vertexer) {
+	sv.StorableVertices = append(sv.StorableVertices, v)
+}
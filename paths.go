@@ -0,0 +1,486 @@
+package dag
+
+// LongestPath returns the ids of the vertices of a path with the maximum
+// number of edges from the vertex with the id fromID to the vertex with the id
+// toID (both inclusive). If there are multiple longest paths, any of them is
+// returned. LongestPath returns an empty slice, if there is no such path and a
+// path consisting of a single vertex, if fromID and toID are equal.
+// LongestPath returns an error, if fromID or toID are empty or unknown.
+//
+// The path is computed in O(V+E) using dynamic programming over a topological
+// order of the graph.
+func (d *DAG) LongestPath(fromID, toID string) ([]string, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if err := d.saneID(fromID); err != nil {
+		return nil, err
+	}
+	if err := d.saneID(toID); err != nil {
+		return nil, err
+	}
+
+	sorted, err := d.topologicalSort()
+	if err != nil {
+		return nil, err
+	}
+
+	// for each vertex reachable from fromID remember the maximum number of edges
+	// from fromID and the predecessor on the respective path
+	length := map[string]int{fromID: 0}
+	predecessors := make(map[string]string)
+	for _, id := range sorted {
+		l, reached := length[id]
+		if !reached {
+			continue
+		}
+		children, _ := d.getChildren(id)
+		for childID := range children {
+			if cl, exists := length[childID]; !exists || l+1 > cl {
+				length[childID] = l + 1
+				predecessors[childID] = id
+			}
+		}
+	}
+
+	return pathTo(fromID, toID, length, predecessors), nil
+}
+
+// ShortestPath returns the ids of the vertices of a path with the fewest edges
+// from the vertex with the id fromID to the vertex with the id toID (both
+// inclusive). ShortestPath returns an empty slice, if there is no such path and
+// a path consisting of a single vertex, if fromID and toID are equal.
+// ShortestPath returns an error, if fromID or toID are empty or unknown.
+//
+// The path is computed using a breadth first search. Children are visited in
+// the order of their ids. Thus, if there are multiple shortest paths, the
+// result is deterministic.
+func (d *DAG) ShortestPath(fromID, toID string) ([]string, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if err := d.saneID(fromID); err != nil {
+		return nil, err
+	}
+	if err := d.saneID(toID); err != nil {
+		return nil, err
+	}
+	return d.shortestPath(fromID, toID), nil
+}
+
+// shortestPath implements ShortestPath for known ids.
+func (d *DAG) shortestPath(fromID, toID string) []string {
+
+	// for each visited vertex remember the number of edges from fromID and the
+	// predecessor on the respective path
+	length := map[string]int{fromID: 0}
+	predecessors := make(map[string]string)
+	fifo := []string{fromID}
+	for len(fifo) > 0 {
+		id := fifo[0]
+		fifo = fifo[1:]
+		if id == toID {
+			break
+		}
+		children, _ := d.getChildren(id)
+		for _, childID := range vertexIDs(children) {
+			if _, exists := length[childID]; !exists {
+				length[childID] = length[id] + 1
+				predecessors[childID] = id
+				fifo = append(fifo, childID)
+			}
+		}
+	}
+
+	return pathTo(fromID, toID, length, predecessors)
+}
+
+// WeightedShortestPath returns the ids of the vertices of a path with the
+// minimum total weight from the vertex with the id fromID to the vertex with
+// the id toID (both inclusive) as well as its total weight. Edges without an
+// explicit weight (see AddWeightedEdge) have a weight of 1.
+// WeightedShortestPath returns an empty slice, if there is no such path and a
+// path consisting of a single vertex, if fromID and toID are equal.
+// WeightedShortestPath returns an error, if fromID or toID are empty or
+// unknown.
+//
+// The path is computed in O(V+E) using dynamic programming over a topological
+// order of the graph. Thus, negative weights are supported as well.
+func (d *DAG) WeightedShortestPath(fromID, toID string) ([]string, float64, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if err := d.saneID(fromID); err != nil {
+		return nil, 0, err
+	}
+	if err := d.saneID(toID); err != nil {
+		return nil, 0, err
+	}
+
+	sorted, err := d.topologicalSort()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// for each vertex reachable from fromID remember the minimum weight from
+	// fromID, the number of edges, and the predecessor on the respective path
+	weight := map[string]float64{fromID: 0}
+	length := map[string]int{fromID: 0}
+	predecessors := make(map[string]string)
+	for _, id := range sorted {
+		w, reached := weight[id]
+		if !reached {
+			continue
+		}
+		vHash := d.hashVertex(d.vertexIds[id])
+		for child := range d.outboundEdge[vHash] {
+			childID := d.vertices[child]
+			cw := w + d.edgeWeight(vHash, child)
+			if oldWeight, exists := weight[childID]; !exists || cw < oldWeight {
+				weight[childID] = cw
+				length[childID] = length[id] + 1
+				predecessors[childID] = id
+			}
+		}
+	}
+
+	return pathTo(fromID, toID, length, predecessors), weight[toID], nil
+}
+
+// CriticalPath returns the ids of the vertices of a path with the maximum
+// total weight from any root to any leaf (i.e. the critical path of the graph
+// in terms of PERT) as well as its total weight. Edges without an explicit
+// weight (see AddWeightedEdge) have a weight of 1. If there are multiple such
+// paths, the one ending at the leaf with the smallest id is returned.
+// CriticalPath returns an empty slice and a weight of 0 for an empty graph and
+// only returns an error, if the graph is inconsistent.
+//
+// The path is computed in O(V+E) using dynamic programming over a topological
+// order of the graph.
+func (d *DAG) CriticalPath() (path []string, total float64, err error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	sorted, err := d.topologicalSort()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// for each vertex remember the maximum weight from any root and the
+	// predecessor on the respective path (roots have no predecessor)
+	weight := make(map[string]float64, len(sorted))
+	predecessors := make(map[string]string)
+	for _, id := range sorted {
+		w := weight[id]
+		vHash := d.hashVertex(d.vertexIds[id])
+		for child := range d.outboundEdge[vHash] {
+			childID := d.vertices[child]
+			cw := w + d.edgeWeight(vHash, child)
+			if _, exists := predecessors[childID]; !exists || cw > weight[childID] {
+				weight[childID] = cw
+				predecessors[childID] = id
+			}
+		}
+	}
+
+	// find the leaf with the maximum weight
+	path = []string{}
+	var last string
+	for _, id := range vertexIDs(d.getLeaves()) {
+		if last == "" || weight[id] > weight[last] {
+			last = id
+		}
+	}
+	if last == "" {
+		return path, 0, nil
+	}
+
+	// follow the predecessors back to a root
+	for id, ok := last, true; ok; id, ok = predecessors[id] {
+		path = append(path, id)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, weight[last], nil
+}
+
+// HasPath returns true, if there is a path from the vertex with the id fromID
+// to the vertex with the id toID. A vertex has a (trivial) path to itself.
+// HasPath returns an error, if fromID or toID are empty or unknown.
+//
+// Note, unlike IsDescendant or GetDescendants, HasPath deliberately neither
+// uses nor populates the descendants-cache, to keep memory bounded on large
+// graphs. Instead, HasPath does a depth-first search that stops as soon as toID
+// is found.
+func (d *DAG) HasPath(fromID, toID string) (bool, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if err := d.saneID(fromID); err != nil {
+		return false, err
+	}
+	if err := d.saneID(toID); err != nil {
+		return false, err
+	}
+	if fromID == toID {
+		return true, nil
+	}
+
+	fromHash := d.hashVertex(d.vertexIds[fromID])
+	toHash := d.hashVertex(d.vertexIds[toID])
+	stack := []interface{}{fromHash}
+	visited := map[interface{}]struct{}{fromHash: {}}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for child := range d.outboundEdge[top] {
+			if child == toHash {
+				return true, nil
+			}
+			if _, exists := visited[child]; !exists {
+				visited[child] = struct{}{}
+				stack = append(stack, child)
+			}
+		}
+	}
+	return false, nil
+}
+
+// AllPaths returns all (distinct) paths from the vertex with the id fromID to
+// the vertex with the id toID. Each path is given by the ids of its vertices
+// (both ends inclusive). Paths are enumerated by a depth-first search visiting
+// children in the order of their ids, thus the order of the result is
+// deterministic. AllPaths returns an empty slice, if there is no such path and
+// a single path consisting of a single vertex, if fromID and toID are equal.
+// AllPaths returns an error, if fromID or toID are empty or unknown.
+//
+// Note, the number of paths may grow exponentially with the size of the graph
+// (e.g. a chain of n diamonds has 2^n paths). Use AllPathsMax to limit the
+// number of paths returned.
+func (d *DAG) AllPaths(fromID, toID string) ([][]string, error) {
+	return d.AllPathsMax(fromID, toID, 0)
+}
+
+// AllPathsMax is like AllPaths but returns at most maxPaths paths. A maxPaths
+// of 0 or less means unlimited.
+func (d *DAG) AllPathsMax(fromID, toID string, maxPaths int) ([][]string, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if err := d.saneID(fromID); err != nil {
+		return nil, err
+	}
+	if err := d.saneID(toID); err != nil {
+		return nil, err
+	}
+
+	// only descend into vertices from which toID is reachable
+	toHash := d.hashVertex(d.vertexIds[toID])
+	ancestors := d.getAncestors(toHash)
+
+	paths := [][]string{}
+	var walk func(id string, path []string) bool
+	walk = func(id string, path []string) bool {
+		path = append(path, id)
+		if id == toID {
+			paths = append(paths, append([]string(nil), path...))
+			return maxPaths <= 0 || len(paths) < maxPaths
+		}
+		children, _ := d.getChildren(id)
+		for _, childID := range vertexIDs(children) {
+			childHash := d.hashVertex(d.vertexIds[childID])
+			if _, exists := ancestors[childHash]; !exists && childHash != toHash {
+				continue
+			}
+			if !walk(childID, path) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(fromID, nil)
+	return paths, nil
+}
+
+// AllMaximalPaths returns all maximal paths of the graph. That is, all paths
+// starting at a root and ending at a leaf. Each path is given by the ids of its
+// vertices. Paths are enumerated by a depth-first search visiting roots and
+// children in the order of their ids, thus the order of the result is
+// deterministic. Vertices without any edges form a path of a single vertex.
+//
+// Note, the number of paths may grow exponentially with the size of the graph
+// (see AllPaths). Use AllMaximalPathsMax to limit the number of paths returned.
+func (d *DAG) AllMaximalPaths() [][]string {
+	return d.AllMaximalPathsMax(0)
+}
+
+// AllMaximalPathsMax is like AllMaximalPaths but returns at most maxPaths
+// paths. A maxPaths of 0 or less means unlimited.
+func (d *DAG) AllMaximalPathsMax(maxPaths int) [][]string {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	paths := [][]string{}
+	var walk func(id string, path []string) bool
+	walk = func(id string, path []string) bool {
+		path = append(path, id)
+		children, _ := d.getChildren(id)
+		if len(children) == 0 {
+			paths = append(paths, append([]string(nil), path...))
+			return maxPaths <= 0 || len(paths) < maxPaths
+		}
+		for _, childID := range vertexIDs(children) {
+			if !walk(childID, path) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, rootID := range vertexIDs(d.getRoots()) {
+		if !walk(rootID, nil) {
+			break
+		}
+	}
+	return paths
+}
+
+// IsOnPath returns true, if the vertex with the id viaID is on at least one
+// path from the vertex with the id fromID to the vertex with the id toID. That
+// is, viaID is a descendant of fromID and an ancestor of toID. The ends of a
+// path are on the path, thus IsOnPath returns true, if viaID equals fromID or
+// toID and there is a path from fromID to toID. IsOnPath returns an error, if
+// any of the ids is empty or unknown.
+//
+// Note, like IsDescendant, IsOnPath neither uses nor populates the caches.
+func (d *DAG) IsOnPath(fromID, toID, viaID string) (bool, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	for _, id := range []string{fromID, toID, viaID} {
+		if err := d.saneID(id); err != nil {
+			return false, err
+		}
+	}
+	reachable := func(srcID, dstID string) bool {
+		if srcID == dstID {
+			return true
+		}
+		srcHash := d.hashVertex(d.vertexIds[srcID])
+		dstHash := d.hashVertex(d.vertexIds[dstID])
+		return d.isReachable(srcHash, dstHash, false)
+	}
+	return reachable(fromID, viaID) && reachable(viaID, toID), nil
+}
+
+// pathTo builds the path from fromID to toID by following the given
+// predecessors backwards. pathTo returns an empty slice, if toID has not been
+// reached (i.e. is not within reached).
+func pathTo(fromID, toID string, reached map[string]int, predecessors map[string]string) []string {
+	if _, exists := reached[toID]; !exists {
+		return []string{}
+	}
+	path := make([]string, reached[toID]+1)
+	id := toID
+	for i := len(path) - 1; i > 0; i-- {
+		path[i] = id
+		id = predecessors[id]
+	}
+	path[0] = fromID
+	return path
+}
+
+// GetDepth returns the depth of the vertex with the id id. That is, the
+// maximum number of edges on any path from a root to the vertex. Roots have a
+// depth of 0. GetDepth returns an error, if id is empty or unknown.
+func (d *DAG) GetDepth(id string) (int, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if err := d.saneID(id); err != nil {
+		return 0, err
+	}
+	return d.getDepth(d.hashVertex(d.vertexIds[id]), make(map[interface{}]int)), nil
+}
+
+// GetHeight returns the height of the vertex with the id id. That is, the
+// maximum number of edges on any path from the vertex to a leaf. Leaves have
+// a height of 0. GetHeight returns an error, if id is empty or unknown.
+func (d *DAG) GetHeight(id string) (int, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if err := d.saneID(id); err != nil {
+		return 0, err
+	}
+	return d.getHeight(d.hashVertex(d.vertexIds[id]), make(map[interface{}]int)), nil
+}
+
+// getDepth returns the depth of vHash memoizing the depths of vHash and all
+// its ancestors in depths.
+func (d *DAG) getDepth(vHash interface{}, depths map[interface{}]int) int {
+	return d.getLongestDistance(vHash, d.inboundEdge, depths)
+}
+
+// getHeight returns the height of vHash memoizing the heights of vHash and all
+// its descendants in heights.
+func (d *DAG) getHeight(vHash interface{}, heights map[interface{}]int) int {
+	return d.getLongestDistance(vHash, d.outboundEdge, heights)
+}
+
+func (d *DAG) getLongestDistance(vHash interface{}, edges map[interface{}]map[interface{}]struct{}, distances map[interface{}]int) int {
+	if distance, exists := distances[vHash]; exists {
+		return distance
+	}
+	distance := 0
+	for relative := range edges[vHash] {
+		if relativeDistance := d.getLongestDistance(relative, edges, distances) + 1; relativeDistance > distance {
+			distance = relativeDistance
+		}
+	}
+	distances[vHash] = distance
+	return distance
+}
+
+// TopologicalLevels returns the ids of all vertices grouped by their depth
+// (see GetDepth). That is, the i-th element contains the ids of all vertices
+// with a depth of i. The ids within each level are sorted.
+func (d *DAG) TopologicalLevels() ([][]string, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.topologicalLevels(), nil
+}
+
+func (d *DAG) topologicalLevels() [][]string {
+	depths := make(map[interface{}]int)
+	levels := make([][]string, 0)
+	for _, id := range vertexIDs(d.vertexIds) {
+		depth := d.getDepth(d.hashVertex(d.vertexIds[id]), depths)
+		for len(levels) <= depth {
+			levels = append(levels, make([]string, 0))
+		}
+		levels[depth] = append(levels[depth], id)
+	}
+	return levels
+}
+
+// Width returns the maximum number of vertices with the same depth (i.e. the
+// size of the largest level of TopologicalLevels). The width of an empty graph
+// is 0.
+//
+// Note, this is the level-based width, which is a lower bound of the true
+// width of the graph (i.e. the size of the maximum anti-chain). For example,
+// the graph 1 --> 2, 1 --> 3 --> 4 plus a single vertex 5 has a width of 2
+// here, although 2, 4, and 5 are pairwise unrelated.
+func (d *DAG) Width() int {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	width := 0
+	for _, level := range d.topologicalLevels() {
+		if len(level) > width {
+			width = len(level)
+		}
+	}
+	return width
+}
@@ -0,0 +1,559 @@
+package dag
+
+import (
+	"github.com/go-test/deep"
+	"strconv"
+	"testing"
+)
+
+// schematic diagram:
+//
+//	1 --> 2 --> 4 --> 5 --> 7
+//	|           |     ^
+//	|           v     |
+//	+---------> 6     |
+//	|                 |
+//	+---------> 3 ----+
+func getTestPathDAG() *DAG {
+	dag := NewDAG()
+	for _, id := range []string{"1", "2", "3", "4", "5", "6", "7"} {
+		_ = dag.AddVertexByID(id, "v"+id)
+	}
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("1", "3")
+	_ = dag.AddEdge("1", "6")
+	_ = dag.AddEdge("2", "4")
+	_ = dag.AddEdge("3", "5")
+	_ = dag.AddEdge("4", "5")
+	_ = dag.AddEdge("4", "6")
+	_ = dag.AddEdge("5", "7")
+	return dag
+}
+
+func TestDAG_LongestPath(t *testing.T) {
+	dag := getTestPathDAG()
+
+	cases := []struct {
+		from, to string
+		expected []string
+	}{
+		{"1", "7", []string{"1", "2", "4", "5", "7"}},
+		{"1", "6", []string{"1", "2", "4", "6"}},
+		{"3", "7", []string{"3", "5", "7"}},
+		{"1", "1", []string{"1"}},
+		{"6", "7", []string{}},
+		{"7", "1", []string{}},
+	}
+	for _, c := range cases {
+		path, err := dag.LongestPath(c.from, c.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equal(path, c.expected) {
+			t.Errorf("LongestPath(%s, %s) = %v, want %v", c.from, c.to, path, c.expected)
+		}
+	}
+
+	// nil
+	_, errNil := dag.LongestPath("", "1")
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("LongestPath(\"\", \"1\") expected IDEmptyError, got %T", errNil)
+	}
+
+	// unknown
+	_, errUnknown := dag.LongestPath("1", "foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("LongestPath(\"1\", \"foo\") expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_ShortestPath(t *testing.T) {
+	dag := getTestPathDAG()
+
+	cases := []struct {
+		from, to string
+		expected []string
+	}{
+		{"1", "7", []string{"1", "3", "5", "7"}},
+		{"1", "6", []string{"1", "6"}},
+		{"1", "5", []string{"1", "3", "5"}},
+		{"2", "7", []string{"2", "4", "5", "7"}},
+		{"1", "1", []string{"1"}},
+		{"6", "7", []string{}},
+		{"7", "1", []string{}},
+	}
+	for _, c := range cases {
+		path, err := dag.ShortestPath(c.from, c.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equal(path, c.expected) {
+			t.Errorf("ShortestPath(%s, %s) = %v, want %v", c.from, c.to, path, c.expected)
+		}
+	}
+
+	// equal length paths are resolved by the order of ids
+	_ = dag.AddVertexByID("0", "v0")
+	_ = dag.AddEdge("2", "0")
+	_ = dag.AddEdge("0", "5")
+	expected := []string{"2", "0", "5"}
+	if path, _ := dag.ShortestPath("2", "5"); !equal(path, expected) {
+		t.Errorf("ShortestPath(2, 5) = %v, want %v", path, expected)
+	}
+
+	// nil
+	_, errNil := dag.ShortestPath("1", "")
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("ShortestPath(\"1\", \"\") expected IDEmptyError, got %T", errNil)
+	}
+
+	// unknown
+	_, errUnknown := dag.ShortestPath("foo", "1")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("ShortestPath(\"foo\", \"1\") expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_CriticalPath(t *testing.T) {
+	dag := NewDAG()
+	path, total, err := dag.CriticalPath()
+	if err != nil || len(path) != 0 || total != 0 {
+		t.Errorf("CriticalPath() = %v, %v, %v, want [], 0, nil", path, total, err)
+	}
+
+	// a --> b --> c --> d (weight 3, 3 edges)
+	// |                 ^
+	// +---> e ----------+ (weight 6, 2 edges)
+	//
+	// plus a single vertex f
+	for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		_ = dag.AddVertexByID(id, id)
+	}
+	_ = dag.AddEdge("a", "b")
+	_ = dag.AddEdge("b", "c")
+	_ = dag.AddEdge("c", "d")
+	_ = dag.AddWeightedEdge("a", "e", 5)
+	_ = dag.AddEdge("e", "d")
+
+	path, total, err = dag.CriticalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"a", "e", "d"}; !equal(path, expected) || total != 6 {
+		t.Errorf("CriticalPath() = %v, %v, want %v, 6", path, total, expected)
+	}
+
+	// without explicit weights the critical path is the longest path
+	_ = dag.DeleteEdge("a", "e")
+	_ = dag.AddEdge("a", "e")
+	path, total, _ = dag.CriticalPath()
+	if expected := []string{"a", "b", "c", "d"}; !equal(path, expected) || total != 3 {
+		t.Errorf("CriticalPath() = %v, %v, want %v, 3", path, total, expected)
+	}
+}
+
+func TestDAG_WeightedShortestPath(t *testing.T) {
+	dag := getTestPathDAG()
+
+	// without weights, the result has the minimum number of edges
+	path, weight, err := dag.WeightedShortestPath("1", "7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"1", "3", "5", "7"}
+	if !equal(path, expected) || weight != 3 {
+		t.Errorf("WeightedShortestPath(1, 7) = %v, %v, want %v, %v", path, weight, expected, 3)
+	}
+
+	// make the edge 3 -> 5 expensive
+	_ = dag.DeleteEdge("3", "5")
+	_ = dag.AddWeightedEdge("3", "5", 10)
+	_ = dag.DeleteEdge("2", "4")
+	_ = dag.AddWeightedEdge("2", "4", 0.5)
+
+	cases := []struct {
+		from, to string
+		expected []string
+		weight   float64
+	}{
+		{"1", "7", []string{"1", "2", "4", "5", "7"}, 3.5},
+		{"1", "6", []string{"1", "6"}, 1},
+		{"3", "7", []string{"3", "5", "7"}, 11},
+		{"1", "1", []string{"1"}, 0},
+		{"6", "7", []string{}, 0},
+	}
+	for _, c := range cases {
+		path, weight, err := dag.WeightedShortestPath(c.from, c.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equal(path, c.expected) || weight != c.weight {
+			t.Errorf("WeightedShortestPath(%s, %s) = %v, %v, want %v, %v", c.from, c.to, path, weight, c.expected, c.weight)
+		}
+	}
+
+	// nil
+	_, _, errNil := dag.WeightedShortestPath("", "1")
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("WeightedShortestPath(\"\", \"1\") expected IDEmptyError, got %T", errNil)
+	}
+
+	// unknown
+	_, _, errUnknown := dag.WeightedShortestPath("1", "foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("WeightedShortestPath(\"1\", \"foo\") expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_GetDepth(t *testing.T) {
+	dag := getTestPathDAG()
+
+	cases := map[string]int{"1": 0, "2": 1, "3": 1, "4": 2, "5": 3, "6": 3, "7": 4}
+	for id, expected := range cases {
+		depth, err := dag.GetDepth(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if depth != expected {
+			t.Errorf("GetDepth(%s) = %d, want %d", id, depth, expected)
+		}
+	}
+
+	// nil
+	_, errNil := dag.GetDepth("")
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("GetDepth(\"\") expected IDEmptyError, got %T", errNil)
+	}
+
+	// unknown
+	_, errUnknown := dag.GetDepth("foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("GetDepth(\"foo\") expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_GetHeight(t *testing.T) {
+	dag := getTestPathDAG()
+
+	cases := map[string]int{"1": 4, "2": 3, "3": 2, "4": 2, "5": 1, "6": 0, "7": 0}
+	for id, expected := range cases {
+		height, err := dag.GetHeight(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if height != expected {
+			t.Errorf("GetHeight(%s) = %d, want %d", id, height, expected)
+		}
+	}
+
+	// nil
+	_, errNil := dag.GetHeight("")
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("GetHeight(\"\") expected IDEmptyError, got %T", errNil)
+	}
+
+	// unknown
+	_, errUnknown := dag.GetHeight("foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("GetHeight(\"foo\") expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_TopologicalLevels(t *testing.T) {
+	dag := NewDAG()
+
+	// empty
+	levels, err := dag.TopologicalLevels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(levels) != 0 {
+		t.Errorf("TopologicalLevels() = %v, want []", levels)
+	}
+
+	// diamond with one branch being longer than the other:
+	//
+	//	1 --> 2 --> 3 --> 5
+	//	|                 ^
+	//	+---> 4 ----------+
+	for _, id := range []string{"1", "2", "3", "4", "5", "6"} {
+		_ = dag.AddVertexByID(id, "v"+id)
+	}
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("2", "3")
+	_ = dag.AddEdge("3", "5")
+	_ = dag.AddEdge("1", "4")
+	_ = dag.AddEdge("4", "5")
+
+	levels, err = dag.TopologicalLevels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{{"1", "6"}, {"2", "4"}, {"3"}, {"5"}}
+	if diff := deep.Equal(levels, expected); diff != nil {
+		t.Errorf("TopologicalLevels() = %v, want %v", levels, expected)
+	}
+
+	// levels are consistent with GetDepth
+	for i, level := range levels {
+		for _, id := range level {
+			if depth, _ := dag.GetDepth(id); depth != i {
+				t.Errorf("GetDepth(%s) = %d, want %d", id, depth, i)
+			}
+		}
+	}
+}
+
+func TestDAG_HasPath(t *testing.T) {
+	dag := getTestPathDAG()
+
+	cases := []struct {
+		from, to string
+		expected bool
+	}{
+		{"1", "7", true},
+		{"3", "7", true},
+		{"2", "6", true},
+		{"1", "1", true},
+		{"6", "7", false},
+		{"7", "1", false},
+		{"3", "4", false},
+	}
+	for _, c := range cases {
+		hasPath, err := dag.HasPath(c.from, c.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hasPath != c.expected {
+			t.Errorf("HasPath(%s, %s) = %v, want %v", c.from, c.to, hasPath, c.expected)
+		}
+	}
+
+	// the recency of cached entries is left untouched
+	limited := getTestPathDAG()
+	limited.Options(Options{MaxCacheEntries: 10})
+	_, _ = limited.GetDescendants("1")
+	_, _ = limited.GetDescendants("2")
+	leastRecent := limited.descendantsLRU.order.Back().Value
+	for _, c := range cases {
+		if hasPath, _ := limited.HasPath(c.from, c.to); hasPath != c.expected {
+			t.Errorf("HasPath(%s, %s) = %v, want %v", c.from, c.to, hasPath, c.expected)
+		}
+	}
+	if back := limited.descendantsLRU.order.Back().Value; back != leastRecent {
+		t.Errorf("least recently used entry = %v, want %v", back, leastRecent)
+	}
+
+	// deep chain without populating the cache
+	chain := NewDAG()
+	_ = chain.AddVertexByID("0", 0)
+	for i := 1; i < 1000; i++ {
+		_ = chain.AddVertexByID(strconv.Itoa(i), i)
+		_ = chain.AddEdge(strconv.Itoa(i-1), strconv.Itoa(i))
+	}
+	chain.FlushCaches()
+	if hasPath, _ := chain.HasPath("0", "999"); !hasPath {
+		t.Errorf("HasPath(0, 999) = false, want true")
+	}
+	if hasPath, _ := chain.HasPath("999", "0"); hasPath {
+		t.Errorf("HasPath(999, 0) = true, want false")
+	}
+	if size := len(chain.descendantsCache); size != 0 {
+		t.Errorf("len(descendantsCache) = %d, want 0", size)
+	}
+
+	// nil
+	_, errNil := dag.HasPath("", "1")
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("HasPath(\"\", \"1\") expected IDEmptyError, got %T", errNil)
+	}
+
+	// unknown
+	_, errUnknown := dag.HasPath("1", "foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("HasPath(\"1\", \"foo\") expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_AllPaths(t *testing.T) {
+
+	// diamond
+	diamond := NewDAG()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		_ = diamond.AddVertexByID(id, id)
+	}
+	_ = diamond.AddEdge("a", "b")
+	_ = diamond.AddEdge("a", "c")
+	_ = diamond.AddEdge("b", "d")
+	_ = diamond.AddEdge("c", "d")
+	paths, err := diamond.AllPaths("a", "d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{{"a", "b", "d"}, {"a", "c", "d"}}
+	if deep.Equal(paths, expected) != nil {
+		t.Errorf("AllPaths(a, d) = %v, want %v", paths, expected)
+	}
+
+	// cap the number of paths
+	paths, _ = diamond.AllPathsMax("a", "d", 1)
+	expected = [][]string{{"a", "b", "d"}}
+	if deep.Equal(paths, expected) != nil {
+		t.Errorf("AllPathsMax(a, d, 1) = %v, want %v", paths, expected)
+	}
+
+	// chain
+	chain := NewDAG()
+	for i := 0; i < 5; i++ {
+		_ = chain.AddVertexByID(strconv.Itoa(i), i)
+		if i > 0 {
+			_ = chain.AddEdge(strconv.Itoa(i-1), strconv.Itoa(i))
+		}
+	}
+	paths, _ = chain.AllPaths("0", "4")
+	expected = [][]string{{"0", "1", "2", "3", "4"}}
+	if deep.Equal(paths, expected) != nil {
+		t.Errorf("AllPaths(0, 4) = %v, want %v", paths, expected)
+	}
+
+	// same vertex and unreachable
+	paths, _ = chain.AllPaths("2", "2")
+	expected = [][]string{{"2"}}
+	if deep.Equal(paths, expected) != nil {
+		t.Errorf("AllPaths(2, 2) = %v, want %v", paths, expected)
+	}
+	paths, _ = chain.AllPaths("4", "0")
+	if paths == nil || len(paths) != 0 {
+		t.Errorf("AllPaths(4, 0) = %v, want []", paths)
+	}
+
+	// unknown
+	_, errUnknown := chain.AllPaths("0", "foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("AllPaths(\"0\", \"foo\") expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_AllMaximalPaths(t *testing.T) {
+	if paths := NewDAG().AllMaximalPaths(); paths == nil || len(paths) != 0 {
+		t.Errorf("AllMaximalPaths() = %v, want []", paths)
+	}
+
+	// diamond
+	diamond := NewDAG()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		_ = diamond.AddVertexByID(id, id)
+	}
+	_ = diamond.AddEdge("a", "b")
+	_ = diamond.AddEdge("a", "c")
+	_ = diamond.AddEdge("b", "d")
+	_ = diamond.AddEdge("c", "d")
+	paths := diamond.AllMaximalPaths()
+	expected := [][]string{{"a", "b", "d"}, {"a", "c", "d"}}
+	if deep.Equal(paths, expected) != nil {
+		t.Errorf("AllMaximalPaths() = %v, want %v", paths, expected)
+	}
+
+	// cap the number of paths
+	paths = diamond.AllMaximalPathsMax(1)
+	expected = [][]string{{"a", "b", "d"}}
+	if deep.Equal(paths, expected) != nil {
+		t.Errorf("AllMaximalPathsMax(1) = %v, want %v", paths, expected)
+	}
+
+	// two disjoint chains plus a single vertex
+	chains := NewDAG()
+	for i := 0; i < 7; i++ {
+		_ = chains.AddVertexByID(strconv.Itoa(i), i)
+	}
+	_ = chains.AddEdge("0", "1")
+	_ = chains.AddEdge("1", "2")
+	_ = chains.AddEdge("3", "4")
+	_ = chains.AddEdge("4", "5")
+	paths = chains.AllMaximalPaths()
+	expected = [][]string{{"0", "1", "2"}, {"3", "4", "5"}, {"6"}}
+	if deep.Equal(paths, expected) != nil {
+		t.Errorf("AllMaximalPaths() = %v, want %v", paths, expected)
+	}
+}
+
+func TestDAG_IsOnPath(t *testing.T) {
+
+	// a --> b --> d --> e
+	// |           ^
+	// +---> c ----+
+	//
+	// plus f --> b
+	dag := NewDAG()
+	for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		_ = dag.AddVertexByID(id, id)
+	}
+	_ = dag.AddEdges([][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}, {"d", "e"}, {"f", "b"}})
+
+	tests := []struct {
+		from, to, via string
+		want          bool
+	}{
+		{"a", "d", "b", true},
+		{"a", "d", "c", true},
+		{"b", "d", "c", false},
+		{"a", "d", "e", false},
+		{"a", "d", "f", false},
+		{"f", "e", "d", true},
+		{"a", "d", "a", true},
+		{"a", "d", "d", true},
+		{"d", "a", "b", false},
+		{"a", "a", "a", true},
+	}
+	for _, tt := range tests {
+		got, err := dag.IsOnPath(tt.from, tt.to, tt.via)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("IsOnPath(%s, %s, %s) = %v, want %v", tt.from, tt.to, tt.via, got, tt.want)
+		}
+	}
+
+	// unknown
+	_, errUnknown := dag.IsOnPath("a", "d", "foo")
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("IsOnPath(a, d, foo) expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_Width(t *testing.T) {
+	dag := NewDAG()
+	if width := dag.Width(); width != 0 {
+		t.Errorf("Width() = %d, want 0", width)
+	}
+
+	// 1 --> 2 --> 5
+	// |           ^
+	// +---> 3 ----+
+	// |
+	// +---> 4
+	for i := 1; i <= 5; i++ {
+		_ = dag.AddVertexByID(strconv.Itoa(i), i)
+	}
+	_ = dag.AddEdge("1", "2")
+	_ = dag.AddEdge("1", "3")
+	_ = dag.AddEdge("1", "4")
+	_ = dag.AddEdge("2", "5")
+	_ = dag.AddEdge("3", "5")
+	if width := dag.Width(); width != 3 {
+		t.Errorf("Width() = %d, want 3", width)
+	}
+
+	// consistent with TopologicalLevels
+	levels, _ := dag.TopologicalLevels()
+	maxLevel := 0
+	for _, level := range levels {
+		if len(level) > maxLevel {
+			maxLevel = len(level)
+		}
+	}
+	if width := dag.Width(); width != maxLevel {
+		t.Errorf("Width() = %d, want %d", width, maxLevel)
+	}
+}
@@ -0,0 +1,66 @@
+package dag
+
+var _ ReadOnlyDAG = (*DAG)(nil)
+
+// ReadOnlyDAG is the interface of the query methods of a DAG. That is, a
+// ReadOnlyDAG doesn't allow to modify the underlying DAG (see ReadOnly).
+//
+// Note, the flows (e.g. DescendantsFlow) are not part of ReadOnlyDAG, as
+// their (callback-) functions are passed the underlying DAG.
+type ReadOnlyDAG interface {
+	GetVertex(id string) (interface{}, error)
+	GetVertexOrDefault(id string, def interface{}) interface{}
+	GetMetadata(id, key string) (interface{}, error)
+	GetVertices() map[string]interface{}
+	SortedVertexIDs() []string
+	IsEdge(srcID, dstID string) (bool, error)
+	IsEdgeV(src, dst interface{}) (bool, error)
+	GetOrder() int
+	GetSize() int
+	Stats() (order, size, roots, leaves int)
+	GetLeaves() map[string]interface{}
+	GetLeavesMap() map[string]interface{}
+	SortedLeafIDs() []string
+	IsLeaf(id string) (bool, error)
+	GetRoots() map[string]interface{}
+	GetRootsMap() map[string]interface{}
+	SortedRootIDs() []string
+	IsRoot(id string) (bool, error)
+	GetParents(id string) (map[string]interface{}, error)
+	GetChildren(id string) (map[string]interface{}, error)
+	GetIncidentEdges(id string) (inbound [][2]string, outbound [][2]string, err error)
+	GetAncestors(id string) (map[string]interface{}, error)
+	AncestorCount(id string) (int, error)
+	GetOrderedAncestors(id string) ([]string, error)
+	AncestorsWalker(id string) (chan string, chan bool, error)
+	GetDescendants(id string) (map[string]interface{}, error)
+	DescendantCount(id string) (int, error)
+	GetOrderedDescendants(id string) ([]string, error)
+	DescendantsWalker(id string) (chan string, chan bool, error)
+	IsDescendant(ancestorID, descendantID string) (bool, error)
+	IsAncestor(id, ancestorID string) (bool, error)
+	FilterDescendants(sourceID string, candidateIDs []string) (map[string]bool, error)
+	TopologicalSort() ([]string, error)
+	ReachablePairs() [][2]string
+	DFSWalk(visitor Visitor)
+	DFSWalkFrom(startID string, visitor Visitor) error
+	BFSWalk(visitor Visitor)
+	BFSWalkFrom(startID string, visitor Visitor) error
+	OrderedWalk(visitor Visitor)
+	OrderedWalkFunc(visitor Visitor, less func(aID, bID string) bool)
+	IsFrozen() bool
+	String() string
+}
+
+// readOnlyDAG hides all methods of the DAG but those of ReadOnlyDAG.
+type readOnlyDAG struct {
+	ReadOnlyDAG
+}
+
+// ReadOnly returns a read-only view of the DAG. The view shares the data (and
+// the locks) of the DAG. That is, modifications of the DAG are visible via the
+// view. The view can't be type asserted to *DAG (or any other type with
+// mutating methods).
+func (d *DAG) ReadOnly() ReadOnlyDAG {
+	return readOnlyDAG{d}
+}
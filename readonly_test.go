@@ -0,0 +1,36 @@
+package dag
+
+import "testing"
+
+func TestDAG_ReadOnly(t *testing.T) {
+	dag := getTestWalkDAG()
+	view := dag.ReadOnly()
+
+	// the view lacks mutating methods and can't be converted back
+	if _, ok := view.(*DAG); ok {
+		t.Errorf("ReadOnly() can be type asserted to *DAG")
+	}
+	type edgeAdder interface {
+		AddEdge(srcID, dstID string) error
+	}
+	if _, ok := view.(edgeAdder); ok {
+		t.Errorf("ReadOnly() has method AddEdge")
+	}
+	if _, ok := view.(interface{ DeleteVertex(id string) error }); ok {
+		t.Errorf("ReadOnly() has method DeleteVertex")
+	}
+
+	if order := view.GetOrder(); order != 5 {
+		t.Errorf("GetOrder() = %d, want 5", order)
+	}
+
+	// the view is live
+	_ = dag.AddEdge("3", "5")
+	if isEdge, _ := view.IsEdge("3", "5"); !isEdge {
+		t.Errorf("IsEdge(3, 5) = false, want true")
+	}
+	descendants, _ := view.GetDescendants("2")
+	if got, want := vertexIDs(descendants), []string{"3", "4", "5"}; !equal(got, want) {
+		t.Errorf("GetDescendants(2) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,133 @@
+package dag
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// vertexTypes is the registry of vertex types (see RegisterVertexType).
+var vertexTypes = struct {
+	sync.RWMutex
+	factories map[string]func() Vertexer
+	tags      map[reflect.Type]string
+}{
+	factories: make(map[string]func() Vertexer),
+	tags:      make(map[reflect.Type]string),
+}
+
+// RegisterVertexType registers the vertex type identified by tag for
+// MarshalTaggedJSON and UnmarshalTaggedJSON. factory must return a pointer to a
+// new (empty) Vertexer to decode a vertex into (as with UnmarshalJSONStream).
+// The type of vertex values identified by tag is the type of the value returned
+// by the Vertex method of such a new Vertexer.
+//
+// RegisterVertexType is meant to be called during initialization. It panics,
+// if tag is empty, factory is nil, the value type can't be determined (i.e.
+// the value is nil), or if tag or the value type are already registered.
+func RegisterVertexType(tag string, factory func() Vertexer) {
+	if tag == "" {
+		panic("dag: RegisterVertexType with empty tag")
+	}
+	if factory == nil {
+		panic("dag: RegisterVertexType with nil factory")
+	}
+	_, value := factory().Vertex()
+	t := reflect.TypeOf(value)
+	if t == nil {
+		panic(fmt.Sprintf("dag: RegisterVertexType with nil value for tag '%s'", tag))
+	}
+
+	vertexTypes.Lock()
+	defer vertexTypes.Unlock()
+	if _, exists := vertexTypes.factories[tag]; exists {
+		panic(fmt.Sprintf("dag: RegisterVertexType called twice for tag '%s'", tag))
+	}
+	if other, exists := vertexTypes.tags[t]; exists {
+		panic(fmt.Sprintf("dag: RegisterVertexType called twice for type %v (tags '%s' and '%s')", t, other, tag))
+	}
+	vertexTypes.factories[tag] = factory
+	vertexTypes.tags[t] = tag
+}
+
+// taggedVertex is a storableVertex with the tag of the type of its value (see
+// RegisterVertexType).
+type taggedVertex struct {
+	WrappedID string      `json:"i"`
+	Tag       string      `json:"t"`
+	Value     interface{} `json:"v"`
+}
+
+// MarshalTaggedJSON returns the JSON encoding of DAG like MarshalJSON but
+// additionally tags each vertex with the tag of the type of its value (see
+// RegisterVertexType). Thus, graphs with vertices of different types may be
+// restored via UnmarshalTaggedJSON. MarshalTaggedJSON returns an error, if the
+// type of any vertex value isn't registered.
+func (d *DAG) MarshalTaggedJSON() ([]byte, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	sd := d.toStorableDAG()
+
+	vertexTypes.RLock()
+	defer vertexTypes.RUnlock()
+	vertices := make([]taggedVertex, 0, len(sd.StorableVertices))
+	for _, v := range sd.StorableVertices {
+		id, value := v.Vertex()
+		tag, exists := vertexTypes.tags[reflect.TypeOf(value)]
+		if !exists {
+			return nil, fmt.Errorf("no vertex type registered for '%s' of type %T", id, value)
+		}
+		vertices = append(vertices, taggedVertex{WrappedID: id, Tag: tag, Value: value})
+	}
+	return json.Marshal(struct {
+		Vertices []taggedVertex `json:"vs"`
+		Edges    []Edger        `json:"es"`
+	}{vertices, sd.StorableEdges})
+}
+
+// UnmarshalTaggedJSON parses the JSON-encoded data written by
+// MarshalTaggedJSON and returns a new DAG with the given options. Each vertex
+// is decoded into a new Vertexer of the factory registered for its tag (see
+// RegisterVertexType). UnmarshalTaggedJSON returns an error, if data isn't
+// valid, if a tag is unknown, or under the same conditions as UnmarshalJSON.
+func UnmarshalTaggedJSON(data []byte, options Options) (*DAG, error) {
+	var td struct {
+		Vertices []json.RawMessage `json:"vs"`
+		Edges    []storableEdge    `json:"es"`
+	}
+	if err := json.Unmarshal(data, &td); err != nil {
+		return nil, err
+	}
+
+	dag := NewDAG()
+	dag.Options(options)
+	for _, raw := range td.Vertices {
+		var tagged struct {
+			Tag string `json:"t"`
+		}
+		if err := json.Unmarshal(raw, &tagged); err != nil {
+			return nil, err
+		}
+		vertexTypes.RLock()
+		factory, exists := vertexTypes.factories[tagged.Tag]
+		vertexTypes.RUnlock()
+		if !exists {
+			return nil, fmt.Errorf("no vertex type registered for tag '%s'", tagged.Tag)
+		}
+		v := factory()
+		if err := json.Unmarshal(raw, v); err != nil {
+			return nil, err
+		}
+		id, value := v.Vertex()
+		if err := dag.AddVertexByID(id, value); err != nil {
+			return nil, fmt.Errorf("failed to add vertex '%s': %w", id, err)
+		}
+	}
+	for _, e := range td.Edges {
+		if err := addEdger(dag, e); err != nil {
+			return nil, err
+		}
+	}
+	return dag, nil
+}
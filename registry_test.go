@@ -0,0 +1,98 @@
+package dag
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+type testPerson struct {
+	Name string `json:"name"`
+}
+
+type testPersonVertex struct {
+	WID string     `json:"i"`
+	Val testPerson `json:"v"`
+}
+
+func (tv testPersonVertex) Vertex() (id string, value interface{}) {
+	return tv.WID, tv.Val
+}
+
+type testPlace struct {
+	City string `json:"city"`
+	Zip  int    `json:"zip"`
+}
+
+type testPlaceVertex struct {
+	WID string    `json:"i"`
+	Val testPlace `json:"v"`
+}
+
+func (tv testPlaceVertex) Vertex() (id string, value interface{}) {
+	return tv.WID, tv.Val
+}
+
+var registerTestVertexTypes sync.Once
+
+func TestMarshalUnmarshalTaggedJSON(t *testing.T) {
+	registerTestVertexTypes.Do(func() {
+		RegisterVertexType("person", func() Vertexer { return &testPersonVertex{} })
+		RegisterVertexType("place", func() Vertexer { return &testPlaceVertex{} })
+	})
+
+	d := NewDAG()
+	_ = d.AddVertexByID("1", testPerson{Name: "Alice"})
+	_ = d.AddVertexByID("2", testPlace{City: "Bern", Zip: 3000})
+	_ = d.AddVertexByID("3", testPerson{Name: "Bob"})
+	_ = d.AddEdge("1", "2")
+	_ = d.AddWeightedEdge("3", "2", 1.5)
+
+	data, err := d.MarshalTaggedJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"vs":[{"i":"1","t":"person","v":{"name":"Alice"}},{"i":"2","t":"place","v":{"city":"Bern","zip":3000}},{"i":"3","t":"person","v":{"name":"Bob"}}],"es":[{"s":"1","d":"2"},{"s":"3","d":"2","w":1.5}]}`
+	if string(data) != expected {
+		t.Errorf("MarshalTaggedJSON() = %s, want %s", data, expected)
+	}
+
+	restored, err := UnmarshalTaggedJSON(data, defaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !restored.Equal(d) {
+		t.Errorf("UnmarshalTaggedJSON() = %v, want %v", restored.String(), d.String())
+	}
+	if v, _ := restored.GetVertex("2"); v != (testPlace{City: "Bern", Zip: 3000}) {
+		t.Errorf("GetVertex(2) = %#v, want %#v", v, testPlace{City: "Bern", Zip: 3000})
+	}
+	if weight, _ := restored.GetEdgeWeight("3", "2"); weight != 1.5 {
+		t.Errorf("GetEdgeWeight(3, 2) = %v, want 1.5", weight)
+	}
+
+	// unregistered type
+	_ = d.AddVertexByID("4", "v4")
+	if _, err := d.MarshalTaggedJSON(); err == nil {
+		t.Errorf("MarshalTaggedJSON() = nil, want an error")
+	}
+
+	// unknown tag
+	unknown := strings.Replace(expected, `"t":"place"`, `"t":"planet"`, 1)
+	if _, err := UnmarshalTaggedJSON([]byte(unknown), defaultOptions()); err == nil {
+		t.Errorf("UnmarshalTaggedJSON() = nil, want an error")
+	}
+
+	// invalid JSON
+	if _, err := UnmarshalTaggedJSON([]byte("{"), defaultOptions()); err == nil {
+		t.Errorf("UnmarshalTaggedJSON() = nil, want an error")
+	}
+
+	// duplicate registration
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("RegisterVertexType() didn't panic for a duplicate tag")
+		}
+	}()
+	RegisterVertexType("person", func() Vertexer { return &testPersonVertex{} })
+}
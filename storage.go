@@ -1,10 +1,12 @@
 package dag
 
 var (
-	_ Vertexer    = (*storableVertex)(nil)
-	_ Edger       = (*storableEdge)(nil)
-	_ StorableDAG = (*storableDAG)(nil)
-	_ IDInterface = (*storableVertex)(nil)
+	_ Vertexer      = (*storableVertex)(nil)
+	_ Edger         = (*storableEdge)(nil)
+	_ WeightedEdger = (*storableEdge)(nil)
+	_ LabeledEdger  = (*storableEdge)(nil)
+	_ StorableDAG   = (*storableDAG)(nil)
+	_ IDInterface   = (*storableVertex)(nil)
 )
 
 // Vertexer is the interface that wraps the basic Vertex method.
@@ -23,6 +25,22 @@ type Edger interface {
 	Edge() (srcID, dstID string)
 }
 
+// WeightedEdger is the (optional) interface of Edgers that carry the weight of
+// their edge. EdgeWeight returns the weight and true, or false, if the edge has
+// no explicit weight (see AddWeightedEdge).
+type WeightedEdger interface {
+	Edger
+	EdgeWeight() (weight float64, ok bool)
+}
+
+// LabeledEdger is the (optional) interface of Edgers that carry the label of
+// their edge. EdgeLabel returns the label and true, or false, if the edge has
+// no label (see SetEdgeLabel).
+type LabeledEdger interface {
+	Edger
+	EdgeLabel() (label interface{}, ok bool)
+}
+
 // StorableDAG is the interface that defines a DAG that can be stored.
 // It provides methods to get all vertices and all edges of a DAG.
 type StorableDAG interface {
@@ -34,8 +52,8 @@ type StorableDAG interface {
 // It is implemented as a storable structure.
 // And it uses short json tag to reduce the number of bytes after serialization.
 type storableVertex struct {
-	WrappedID string      `json:"i"`
-	Value     interface{} `json:"v"`
+	WrappedID string      `json:"i" yaml:"i"`
+	Value     interface{} `json:"v" yaml:"v"`
 }
 
 func (v storableVertex) Vertex() (id string, value interface{}) {
@@ -49,21 +67,37 @@ func (v storableVertex) ID() string {
 // storableEdge implements the Edger interface.
 // It is implemented as a storable structure.
 // And it uses short json tag to reduce the number of bytes after serialization.
+// Weight and Label are only set for edges with an explicit weight or label
+// (and omitted otherwise). Labels are decoded as the respective generic types
+// (e.g. map[string]interface{} for JSON objects).
 type storableEdge struct {
-	SrcID string `json:"s"`
-	DstID string `json:"d"`
+	SrcID  string      `json:"s" yaml:"s"`
+	DstID  string      `json:"d" yaml:"d"`
+	Weight *float64    `json:"w,omitempty" yaml:"w,omitempty"`
+	Label  interface{} `json:"l,omitempty" yaml:"l,omitempty"`
 }
 
 func (e storableEdge) Edge() (srcID, dstID string) {
 	return e.SrcID, e.DstID
 }
 
+func (e storableEdge) EdgeWeight() (weight float64, ok bool) {
+	if e.Weight == nil {
+		return 0, false
+	}
+	return *e.Weight, true
+}
+
+func (e storableEdge) EdgeLabel() (label interface{}, ok bool) {
+	return e.Label, e.Label != nil
+}
+
 // storableDAG implements the StorableDAG interface.
 // It acts as a serializable operable structure.
 // And it uses short json tag to reduce the number of bytes after serialization.
 type storableDAG struct {
-	StorableVertices []Vertexer `json:"vs"`
-	StorableEdges    []Edger    `json:"es"`
+	StorableVertices []Vertexer `json:"vs" yaml:"vs"`
+	StorableEdges    []Edger    `json:"es" yaml:"es"`
 }
 
 func (g storableDAG) Vertices() []Vertexer {
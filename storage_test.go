@@ -1,8 +1,8 @@
 package dag
 
 type testVertex struct {
-	WID string `json:"i"`
-	Val string `json:"v"`
+	WID string `json:"i" yaml:"i"`
+	Val string `json:"v" yaml:"v"`
 }
 
 func (tv testVertex) ID() string {
@@ -14,8 +14,8 @@ func (tv testVertex) Vertex() (id string, value interface{}) {
 }
 
 type testStorableDAG struct {
-	StorableVertices []testVertex   `json:"vs"`
-	StorableEdges    []storableEdge `json:"es"`
+	StorableVertices []testVertex   `json:"vs" yaml:"vs"`
+	StorableEdges    []storableEdge `json:"es" yaml:"es"`
 }
 
 func (g testStorableDAG) Vertices() []Vertexer {
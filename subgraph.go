@@ -0,0 +1,74 @@
+package dag
+
+// SubgraphFrom returns a new DAG containing the vertices with the given ids
+// (i.e. the seeds) and all their descendants as well as all edges between
+// these vertices (including their weights and labels). The vertices of the new
+// DAG have the same ids and values. SubgraphFrom returns an error, if any of
+// the ids is empty or unknown.
+func (d *DAG) SubgraphFrom(ids []string) (*DAG, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	for _, id := range ids {
+		if err := d.saneID(id); err != nil {
+			return nil, err
+		}
+	}
+
+	hashes := make(map[interface{}]struct{})
+	for _, id := range ids {
+		vHash := d.hashVertex(d.vertexIds[id])
+		hashes[vHash] = struct{}{}
+		for descendant := range d.getDescendants(vHash) {
+			hashes[descendant] = struct{}{}
+		}
+	}
+	return d.inducedSubgraph(hashes)
+}
+
+// InducedSubgraph returns a new DAG containing exactly the vertices with the
+// given ids and all edges between these vertices including their weights and
+// labels (i.e. edges to or from other vertices are excluded). The vertices of
+// the new DAG have the same ids and values. InducedSubgraph returns an error,
+// if any of the ids is empty or unknown.
+func (d *DAG) InducedSubgraph(ids []string) (*DAG, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	hashes := make(map[interface{}]struct{}, len(ids))
+	for _, id := range ids {
+		if err := d.saneID(id); err != nil {
+			return nil, err
+		}
+		hashes[d.hashVertex(d.vertexIds[id])] = struct{}{}
+	}
+	return d.inducedSubgraph(hashes)
+}
+
+// inducedSubgraph returns a new DAG containing the vertices with the given
+// hashes and all edges between these vertices (including their weights and
+// labels).
+func (d *DAG) inducedSubgraph(hashes map[interface{}]struct{}) (*DAG, error) {
+	newDAG := NewDAG()
+	newDAG.Options(d.options)
+	newHashes := make(map[interface{}]interface{}, len(hashes))
+	for vHash := range hashes {
+		id := d.vertices[vHash]
+		if err := newDAG.AddVertexByID(id, d.vertexIds[id]); err != nil {
+			return nil, err
+		}
+		newHashes[vHash] = newDAG.hashVertex(d.vertexIds[id])
+	}
+	for src := range hashes {
+		for dst := range d.outboundEdge[src] {
+			if _, exists := hashes[dst]; !exists {
+				continue
+			}
+			if err := newDAG.AddEdge(d.vertices[src], d.vertices[dst]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	d.copyEdgeAttributes(newDAG, newHashes)
+	return newDAG, nil
+}
@@ -0,0 +1,143 @@
+package dag
+
+import "testing"
+
+func TestDAG_SubgraphFrom(t *testing.T) {
+	dag := getTestPathDAG()
+
+	// the descendants of 3 (5 and 7) are descendants of 4 as well
+	subgraph, err := dag.SubgraphFrom([]string{"4", "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := NewDAG()
+	for _, id := range []string{"3", "4", "5", "6", "7"} {
+		_ = expected.AddVertexByID(id, "v"+id)
+	}
+	_ = expected.AddEdge("3", "5")
+	_ = expected.AddEdge("4", "5")
+	_ = expected.AddEdge("4", "6")
+	_ = expected.AddEdge("5", "7")
+	if !subgraph.Equal(expected) {
+		t.Errorf("SubgraphFrom([4, 3]) = %v, want %v", subgraph.String(), expected.String())
+	}
+
+	// all roots
+	subgraph, _ = dag.SubgraphFrom([]string{"1"})
+	if !subgraph.Equal(dag) {
+		t.Errorf("SubgraphFrom([1]) = %v, want %v", subgraph.String(), dag.String())
+	}
+
+	// no seeds
+	subgraph, _ = dag.SubgraphFrom(nil)
+	if order := subgraph.GetOrder(); order != 0 {
+		t.Errorf("SubgraphFrom(nil).GetOrder() = %d, want 0", order)
+	}
+
+	// nil
+	_, errNil := dag.SubgraphFrom([]string{"1", ""})
+	if _, ok := errNil.(IDEmptyError); !ok {
+		t.Errorf("SubgraphFrom([1, \"\"]) expected IDEmptyError, got %T", errNil)
+	}
+
+	// unknown
+	_, errUnknown := dag.SubgraphFrom([]string{"foo"})
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("SubgraphFrom([foo]) expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestDAG_InducedSubgraph(t *testing.T) {
+	dag := getTestPathDAG()
+
+	// the edges 1 -> 3, 3 -> 5, and 5 -> 7 cross the boundary
+	subgraph, err := dag.InducedSubgraph([]string{"1", "2", "4", "5", "6"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := NewDAG()
+	for _, id := range []string{"1", "2", "4", "5", "6"} {
+		_ = expected.AddVertexByID(id, "v"+id)
+	}
+	_ = expected.AddEdge("1", "2")
+	_ = expected.AddEdge("1", "6")
+	_ = expected.AddEdge("2", "4")
+	_ = expected.AddEdge("4", "5")
+	_ = expected.AddEdge("4", "6")
+	if !subgraph.Equal(expected) {
+		t.Errorf("InducedSubgraph() = %v, want %v", subgraph.String(), expected.String())
+	}
+
+	// no edges between the vertices
+	subgraph, _ = dag.InducedSubgraph([]string{"1", "4", "7"})
+	if order, size := subgraph.GetOrder(), subgraph.GetSize(); order != 3 || size != 0 {
+		t.Errorf("InducedSubgraph([1, 4, 7]) has %d vertices and %d edges, want 3 and 0", order, size)
+	}
+
+	// unknown
+	_, errUnknown := dag.InducedSubgraph([]string{"1", "foo"})
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("InducedSubgraph([1, foo]) expected IDUnknownError, got %T", errUnknown)
+	}
+}
+
+func TestSubgraphsEdgeAttributes(t *testing.T) {
+
+	// 0 --> 1 --5--> 2 --> 3
+	//       |              ^
+	//       +------x-------+
+	dag := NewDAG()
+	for i := 0; i < 4; i++ {
+		_, _ = dag.AddVertex(iVertex{i})
+	}
+	_ = dag.AddEdge("0", "1")
+	_ = dag.AddWeightedEdge("1", "2", 5)
+	_ = dag.AddEdge("2", "3")
+	_ = dag.AddEdge("1", "3")
+	_ = dag.SetEdgeLabel("1", "3", "x")
+
+	descendants, id, err := dag.GetDescendantsGraph("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ancestors, _, err := dag.GetAncestorsGraph("3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	maxDepth, _, err := dag.GetDescendantsGraphMaxDepth("1", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := dag.SubgraphFrom([]string{"1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	induced, err := dag.InducedSubgraph([]string{"1", "2", "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "1" {
+		t.Fatalf("GetDescendantsGraph(1) returned id %s, want 1", id)
+	}
+
+	for name, subgraph := range map[string]*DAG{
+		"GetDescendantsGraph":         descendants,
+		"GetAncestorsGraph":           ancestors,
+		"GetDescendantsGraphMaxDepth": maxDepth,
+		"SubgraphFrom":                from,
+		"InducedSubgraph":             induced,
+	} {
+		if weight, _ := subgraph.GetEdgeWeight("1", "2"); weight != 5 {
+			t.Errorf("%s: GetEdgeWeight(1, 2) = %v, want 5", name, weight)
+		}
+		if label, _ := subgraph.GetEdgeLabel("1", "3"); label != "x" {
+			t.Errorf("%s: GetEdgeLabel(1, 3) = %v, want x", name, label)
+		}
+	}
+
+	// the critical path of the extracted graph takes the weights into account
+	path, total, _ := descendants.CriticalPath()
+	if expected := []string{"1", "2", "3"}; !equal(path, expected) || total != 6 {
+		t.Errorf("CriticalPath() = %v, %v, want %v, 6", path, total, expected)
+	}
+}
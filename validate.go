@@ -0,0 +1,70 @@
+package dag
+
+import "fmt"
+
+// Validate checks the internal consistency of the graph. That is, Validate
+// checks that vertices and ids are mapped to each other, that all in- and
+// outbound edges match and only reference known vertices, that the cached
+// number of edges is correct, and that the graph doesn't contain a cycle.
+// Validate returns an error describing the first inconsistency found.
+//
+// As all operations keep the graph consistent, Validate should never return
+// an error. It is meant as a safeguard for tests and for detecting bugs.
+func (d *DAG) Validate() error {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.validate()
+}
+
+func (d *DAG) validate() error {
+	if len(d.vertices) != len(d.vertexIds) {
+		return fmt.Errorf("%d vertices but %d ids", len(d.vertices), len(d.vertexIds))
+	}
+	for _, id := range vertexIDs(d.vertexIds) {
+		vHash := d.hashVertex(d.vertexIds[id])
+		if vID, exists := d.vertices[vHash]; !exists || vID != id {
+			return fmt.Errorf("vertex with id '%s' is not mapped to its id", id)
+		}
+	}
+
+	count := 0
+	for srcHash, dstHashes := range d.outboundEdge {
+		srcID, exists := d.vertices[srcHash]
+		if !exists {
+			return fmt.Errorf("outbound edges of unknown vertex '%v'", srcHash)
+		}
+		for dstHash := range dstHashes {
+			dstID, exists := d.vertices[dstHash]
+			if !exists {
+				return fmt.Errorf("outbound edge from '%s' to unknown vertex '%v'", srcID, dstHash)
+			}
+			if _, exists := d.inboundEdge[dstHash][srcHash]; !exists {
+				return fmt.Errorf("outbound edge from '%s' to '%s' without inbound edge", srcID, dstID)
+			}
+			count++
+		}
+	}
+	for dstHash, srcHashes := range d.inboundEdge {
+		dstID, exists := d.vertices[dstHash]
+		if !exists {
+			return fmt.Errorf("inbound edges of unknown vertex '%v'", dstHash)
+		}
+		for srcHash := range srcHashes {
+			srcID, exists := d.vertices[srcHash]
+			if !exists {
+				return fmt.Errorf("inbound edge from unknown vertex '%v' to '%s'", srcHash, dstID)
+			}
+			if _, exists := d.outboundEdge[srcHash][dstHash]; !exists {
+				return fmt.Errorf("inbound edge from '%s' to '%s' without outbound edge", srcID, dstID)
+			}
+		}
+	}
+	if count != d.edgeCount {
+		return fmt.Errorf("%d edges but edge count is %d", count, d.edgeCount)
+	}
+
+	if _, err := d.topologicalSort(); err != nil {
+		return fmt.Errorf("graph contains a cycle: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package dag
+
+import "testing"
+
+func TestDAG_Validate(t *testing.T) {
+	dag := getTestWalkDAG()
+	if err := dag.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	// still valid after mutation
+	_ = dag.DeleteVertex("2")
+	_ = dag.DeleteEdge("1", "3")
+	dag.ReduceTransitively()
+	if err := dag.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	// missing inbound edge
+	dag = getTestWalkDAG()
+	delete(dag.inboundEdge[dag.hashVertex("v3")], dag.hashVertex("v2"))
+	if err := dag.Validate(); err == nil {
+		t.Errorf("Validate() = nil, want error")
+	}
+
+	// wrong edge count
+	dag = getTestWalkDAG()
+	dag.edgeCount++
+	if err := dag.Validate(); err == nil {
+		t.Errorf("Validate() = nil, want error")
+	}
+
+	// unknown vertex
+	dag = getTestWalkDAG()
+	dag.outboundEdge["foo"] = map[interface{}]struct{}{}
+	if err := dag.Validate(); err == nil {
+		t.Errorf("Validate() = nil, want error")
+	}
+
+	// cycle
+	dag = getTestWalkDAG()
+	v1, v5 := dag.hashVertex("v1"), dag.hashVertex("v5")
+	dag.outboundEdge[v5] = map[interface{}]struct{}{v1: {}}
+	dag.inboundEdge[v1] = map[interface{}]struct{}{v5: {}}
+	dag.edgeCount++
+	if err := dag.Validate(); err == nil {
+		t.Errorf("Validate() = nil, want error")
+	}
+}
@@ -1,6 +1,7 @@
 package dag
 
 import (
+	"container/heap"
 	"sort"
 
 	llq "github.com/emirpasic/gods/queues/linkedlistqueue"
@@ -17,14 +18,36 @@ type Visitor interface {
 // DFSWalk implements the Depth-First-Search algorithm to traverse the entire DAG.
 // The algorithm starts at the root node and explores as far as possible
 // along each branch before backtracking.
+//
+// Roots and children are explored in ascending order of their ids. Thus, the
+// order in which vertices are visited is deterministic (i.e. two consecutive
+// runs of DFSWalk on the same graph visit the vertices in the same order).
 func (d *DAG) DFSWalk(visitor Visitor) {
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
+	d.dfsWalk(vertexIDs(d.getRoots()), visitor)
+}
+
+// DFSWalkFrom is like DFSWalk but starts at the vertex with the id startID
+// instead of the roots. That is, it only visits this vertex and all its
+// descendants. DFSWalkFrom returns an error, if startID is empty or unknown.
+func (d *DAG) DFSWalkFrom(startID string, visitor Visitor) error {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(startID); err != nil {
+		return err
+	}
+	d.dfsWalk([]string{startID}, visitor)
+	return nil
+}
 
+// dfsWalk implements DFSWalk starting at the vertices with the given (sorted)
+// ids.
+func (d *DAG) dfsWalk(startIDs []string, visitor Visitor) {
 	stack := lls.New()
 
-	vertices := d.getRoots()
-	for _, id := range reversedVertexIDs(vertices) {
+	for i := len(startIDs) - 1; i >= 0; i-- {
+		id := startIDs[i]
 		v := d.vertexIds[id]
 		sv := storableVertex{WrappedID: id, Value: v}
 		stack.Push(sv)
@@ -50,18 +73,84 @@ func (d *DAG) DFSWalk(visitor Visitor) {
 	}
 }
 
+// DFSWalkPostOrder implements the Depth-First-Search algorithm to traverse the
+// entire DAG in post-order. Like DFSWalk, it starts at the roots and explores as
+// far as possible along each branch, but it visits a vertex only after all of
+// its descendants have been visited (i.e. children before parents). Roots and
+// children are explored in the order of their ids.
+func (d *DAG) DFSWalkPostOrder(visitor Visitor) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	stack := lls.New()
+
+	vertices := d.getRoots()
+	for _, id := range reversedVertexIDs(vertices) {
+		stack.Push(id)
+	}
+
+	visited := make(map[string]bool, d.getOrder())
+	expanded := make(map[string]bool, d.getOrder())
+
+	for !stack.Empty() {
+		top, _ := stack.Peek()
+		id := top.(string)
+
+		if visited[id] {
+			stack.Pop()
+			continue
+		}
+
+		// first explore all children, and visit the vertex when coming back to it
+		if !expanded[id] {
+			expanded[id] = true
+			vertices, _ := d.getChildren(id)
+			for _, childID := range reversedVertexIDs(vertices) {
+				if !visited[childID] {
+					stack.Push(childID)
+				}
+			}
+			continue
+		}
+
+		stack.Pop()
+		visited[id] = true
+		visitor.Visit(storableVertex{WrappedID: id, Value: d.vertexIds[id]})
+	}
+}
+
 // BFSWalk implements the Breadth-First-Search algorithm to traverse the entire DAG.
 // It starts at the tree root and explores all nodes at the present depth prior
 // to moving on to the nodes at the next depth level.
+//
+// Like for DFSWalk, roots and children are explored in ascending order of
+// their ids, such that the order of visits is deterministic.
 func (d *DAG) BFSWalk(visitor Visitor) {
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
+	d.bfsWalk(vertexIDs(d.getRoots()), visitor)
+}
+
+// BFSWalkFrom is like BFSWalk but starts at the vertex with the id startID
+// instead of the roots. That is, it only visits this vertex and all its
+// descendants. BFSWalkFrom returns an error, if startID is empty or unknown.
+func (d *DAG) BFSWalkFrom(startID string, visitor Visitor) error {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(startID); err != nil {
+		return err
+	}
+	d.bfsWalk([]string{startID}, visitor)
+	return nil
+}
 
+// bfsWalk implements BFSWalk starting at the vertices with the given (sorted)
+// ids.
+func (d *DAG) bfsWalk(startIDs []string, visitor Visitor) {
 	queue := llq.New()
 
-	vertices := d.getRoots()
-	for _, id := range vertexIDs(vertices) {
-		v := vertices[id]
+	for _, id := range startIDs {
+		v := d.vertexIds[id]
 		sv := storableVertex{WrappedID: id, Value: v}
 		queue.Enqueue(sv)
 	}
@@ -86,6 +175,7 @@ func (d *DAG) BFSWalk(visitor Visitor) {
 	}
 }
 
+// vertexIDs returns the ids of the given vertices in ascending order.
 func vertexIDs(vertices map[string]interface{}) []string {
 	ids := make([]string, 0, len(vertices))
 	for id := range vertices {
@@ -134,7 +224,7 @@ Main:
 
 		// if the current vertex has any parent that hasn't been visited yet,
 		// put it back into the queue, and work on the next element
-		parents, _ := d.GetParents(sv.WrappedID)
+		parents, _ := d.getParents(sv.WrappedID)
 		for parent := range parents {
 			if !visited[parent] {
 				queue.Enqueue(sv)
@@ -155,3 +245,56 @@ Main:
 		}
 	}
 }
+
+// OrderedWalkFunc is like OrderedWalk (i.e. for any edge a -> b, the vertex a
+// is visited before the vertex b), but the order of vertices that are ready to
+// be visited (i.e. whose parents have all been visited) is given by less. That
+// is, of all ready vertices, the one that is the smallest in terms of less is
+// visited next. If less is nil, the ids are compared.
+func (d *DAG) OrderedWalkFunc(visitor Visitor, less func(aID, bID string) bool) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if less == nil {
+		less = func(aID, bID string) bool { return aID < bID }
+	}
+
+	// the number of parents not yet visited per vertex
+	pending := make(map[string]int, d.getOrder())
+	ready := &readyHeap{less: less}
+	for id, v := range d.vertexIds {
+		pending[id] = len(d.inboundEdge[d.hashVertex(v)])
+		if pending[id] == 0 {
+			ready.ids = append(ready.ids, id)
+		}
+	}
+	heap.Init(ready)
+
+	for ready.Len() > 0 {
+		id := heap.Pop(ready).(string)
+		visitor.Visit(storableVertex{WrappedID: id, Value: d.vertexIds[id]})
+		children, _ := d.getChildren(id)
+		for childID := range children {
+			pending[childID]--
+			if pending[childID] == 0 {
+				heap.Push(ready, childID)
+			}
+		}
+	}
+}
+
+// readyHeap implements heap.Interface for the ids of vertices ordered by less.
+type readyHeap struct {
+	ids  []string
+	less func(aID, bID string) bool
+}
+
+func (h *readyHeap) Len() int           { return len(h.ids) }
+func (h *readyHeap) Less(i, j int) bool { return h.less(h.ids[i], h.ids[j]) }
+func (h *readyHeap) Swap(i, j int)      { h.ids[i], h.ids[j] = h.ids[j], h.ids[i] }
+func (h *readyHeap) Push(x interface{}) { h.ids = append(h.ids, x.(string)) }
+func (h *readyHeap) Pop() interface{} {
+	id := h.ids[len(h.ids)-1]
+	h.ids = h.ids[:len(h.ids)-1]
+	return id
+}
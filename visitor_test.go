@@ -1,7 +1,10 @@
 package dag
 
 import (
+	"fmt"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/go-test/deep"
 )
@@ -186,6 +189,114 @@ func TestDFSWalk(t *testing.T) {
 	}
 }
 
+func TestWalkDeterministic(t *testing.T) {
+
+	// a wide graph, such that map iteration order would likely show
+	d := NewDAG()
+	_ = d.AddVertexByID("r", "r")
+	var children []string
+	for i := 0; i < 20; i++ {
+		id := fmt.Sprintf("c%02d", i)
+		children = append(children, id)
+		_ = d.AddVertexByID(id, id)
+		_ = d.AddEdge("r", id)
+	}
+
+	walks := map[string]func(Visitor){"DFSWalk": d.DFSWalk, "BFSWalk": d.BFSWalk}
+	for name, walk := range walks {
+		for i := 0; i < 10; i++ {
+			pv := &testVisitor{}
+			walk(pv)
+			expected := append([]string{"r"}, children...)
+			if deep.Equal(expected, pv.Values) != nil {
+				t.Fatalf("%s() = %v, want %v", name, pv.Values, expected)
+			}
+		}
+	}
+}
+
+func TestWalkFrom(t *testing.T) {
+	dag := getTestWalkDAG()
+
+	pv := &testVisitor{}
+	if err := dag.DFSWalkFrom("2", pv); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"v2", "v3", "v4", "v5"}
+	if deep.Equal(expected, pv.Values) != nil {
+		t.Errorf("DFSWalkFrom(2) = %v, want %v", pv.Values, expected)
+	}
+
+	pv = &testVisitor{}
+	if err := dag.BFSWalkFrom("4", pv); err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{"v4", "v5"}
+	if deep.Equal(expected, pv.Values) != nil {
+		t.Errorf("BFSWalkFrom(4) = %v, want %v", pv.Values, expected)
+	}
+
+	pv = &testVisitor{}
+	_ = dag.BFSWalkFrom("5", pv)
+	expected = []string{"v5"}
+	if deep.Equal(expected, pv.Values) != nil {
+		t.Errorf("BFSWalkFrom(5) = %v, want %v", pv.Values, expected)
+	}
+
+	// unknown
+	pv = &testVisitor{}
+	errUnknown := dag.DFSWalkFrom("foo", pv)
+	if _, ok := errUnknown.(IDUnknownError); !ok {
+		t.Errorf("DFSWalkFrom(foo) expected IDUnknownError, got %T", errUnknown)
+	}
+	if len(pv.Values) != 0 {
+		t.Errorf("DFSWalkFrom(foo) visited %v, want none", pv.Values)
+	}
+	errEmpty := dag.BFSWalkFrom("", pv)
+	if _, ok := errEmpty.(IDEmptyError); !ok {
+		t.Errorf("BFSWalkFrom(\"\") expected IDEmptyError, got %T", errEmpty)
+	}
+}
+
+func TestDFSWalkPostOrder(t *testing.T) {
+	cases := []struct {
+		dag      *DAG
+		expected []string
+	}{
+		{
+			dag:      getTestWalkDAG(),
+			expected: []string{"v3", "v5", "v4", "v2", "v1"},
+		},
+		{
+			dag:      getTestWalkDAG2(),
+			expected: []string{"v5", "v3", "v1", "v2", "v4"},
+		},
+		{
+			dag:      getTestWalkDAG3(),
+			expected: []string{"v3", "v1", "v2", "v5", "v4"},
+		},
+		{
+			dag:      getTestWalkDAG4(),
+			expected: []string{"v5", "v3", "v4", "v2", "v1"},
+		},
+		{
+			dag:      getTestWalkDAG5(),
+			expected: []string{"v5", "v3", "v1", "v4", "v2"},
+		},
+	}
+
+	for _, c := range cases {
+		pv := &testVisitor{}
+		c.dag.DFSWalkPostOrder(pv)
+
+		expected := c.expected
+		actual := pv.Values
+		if deep.Equal(expected, actual) != nil {
+			t.Errorf("DFSWalkPostOrder() = %v, want %v", actual, expected)
+		}
+	}
+}
+
 func TestBFSWalk(t *testing.T) {
 	cases := []struct {
 		dag      *DAG
@@ -263,3 +374,76 @@ func TestOrderedWalk(t *testing.T) {
 		}
 	}
 }
+
+func TestOrderedWalkFunc(t *testing.T) {
+
+	//	1 --> 3 --> 5
+	//	      ^
+	//	      |
+	//	2 --> 4
+	dag := NewDAG()
+	for i := 1; i <= 5; i++ {
+		_ = dag.AddVertexByID(strconv.Itoa(i), fmt.Sprintf("v%d", i))
+	}
+	_ = dag.AddEdge("1", "3")
+	_ = dag.AddEdge("3", "5")
+	_ = dag.AddEdge("2", "4")
+	_ = dag.AddEdge("4", "3")
+
+	// reversed numeric ids
+	greater := func(aID, bID string) bool {
+		a, _ := strconv.Atoi(aID)
+		b, _ := strconv.Atoi(bID)
+		return a > b
+	}
+	pv := &testVisitor{}
+	dag.OrderedWalkFunc(pv, greater)
+	expected := []string{"v2", "v4", "v1", "v3", "v5"}
+	if deep.Equal(expected, pv.Values) != nil {
+		t.Errorf("OrderedWalkFunc() = %v, want %v", pv.Values, expected)
+	}
+
+	// by id
+	pv = &testVisitor{}
+	dag.OrderedWalkFunc(pv, nil)
+	expected = []string{"v1", "v2", "v4", "v3", "v5"}
+	if deep.Equal(expected, pv.Values) != nil {
+		t.Errorf("OrderedWalkFunc() = %v, want %v", pv.Values, expected)
+	}
+}
+
+func TestOrderedWalkConcurrentMutation(t *testing.T) {
+	dag := getTestWalkDAG()
+	_ = dag.AddVertexByID("6", "v6")
+
+	// repeatedly add and delete edges while walking
+	stop := make(chan struct{})
+	defer close(stop)
+	for _, src := range []string{"3", "4", "5"} {
+		go func(src string) {
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					_ = dag.AddEdge(src, "6")
+					_ = dag.DeleteEdge(src, "6")
+				}
+			}
+		}(src)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 1000; i++ {
+			dag.OrderedWalk(&testVisitor{})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("OrderedWalk() did not complete within 10s")
+	}
+}
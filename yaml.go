@@ -0,0 +1,45 @@
+//go:build yaml
+
+// YAML support is optional. That is, it is only built with the build tag
+// "yaml" (e.g. `go build -tags yaml`), such that users not needing it don't
+// compile gopkg.in/yaml.v3 into their binaries.
+
+package dag
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalYAML implements the yaml.Marshaler interface (i.e. it allows
+// yaml.Marshal(d)). It returns the vertices and edges of the DAG in the same
+// structure and order as MarshalJSON (including edge weights and labels).
+func (d *DAG) MarshalYAML() (interface{}, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.toStorableDAG(), nil
+}
+
+// UnmarshalYAML parses the YAML-encoded data that defined by StorableDAG.
+// It returns a new DAG (with default options) defined by the vertices and
+// edges of wd. Like UnmarshalJSON, UnmarshalYAML needs to be passed a
+// concrete StorableDAG, as the type of the vertex values is unknown
+// otherwise.
+//
+// Example:
+//
+//	data, err := yaml.Marshal(d)
+//	if err != nil {
+//		panic(err)
+//	}
+//	var wd YourStorableDAG
+//	restoredDag, err := UnmarshalYAML(data, &wd)
+//	if err != nil {
+//		panic(err)
+//	}
+func UnmarshalYAML(data []byte, wd StorableDAG) (*DAG, error) {
+	err := yaml.Unmarshal(data, wd)
+	if err != nil {
+		return nil, err
+	}
+	return fromStorableDAG(wd, defaultOptions())
+}
@@ -0,0 +1,79 @@
+//go:build yaml
+
+package dag
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMarshalUnmarshalYAML(t *testing.T) {
+	for _, d := range []*DAG{getTestWalkDAG(), getTestWalkDAG2(), getTestWalkDAG3(), getTestWalkDAG4()} {
+		data, err := yaml.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var wd testStorableDAG
+		dag, err := UnmarshalYAML(data, &wd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !d.Equal(dag) {
+			t.Errorf("UnmarshalYAML() = %v, want %v", dag.String(), d.String())
+		}
+	}
+
+	// encoding
+	expected := `vs:
+    - i: "1"
+      v: v1
+    - i: "2"
+      v: v2
+    - i: "3"
+      v: v3
+    - i: "4"
+      v: v4
+    - i: "5"
+      v: v5
+es:
+    - s: "1"
+      d: "2"
+    - s: "2"
+      d: "3"
+    - s: "2"
+      d: "4"
+    - s: "4"
+      d: "5"
+`
+	data, _ := yaml.Marshal(getTestWalkDAG())
+	if string(data) != expected {
+		t.Errorf("Marshal() = %s, want %s", data, expected)
+	}
+
+	// edge attributes
+	d := getTestWalkDAG()
+	_ = d.DeleteEdge("1", "2")
+	_ = d.AddWeightedEdge("1", "2", 2.5)
+	_ = d.SetEdgeLabel("2", "3", "depends")
+	data, _ = yaml.Marshal(d)
+	var wdAttributes testStorableDAG
+	restored, err := UnmarshalYAML(data, &wdAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weight, _ := restored.GetEdgeWeight("1", "2"); weight != 2.5 {
+		t.Errorf("GetEdgeWeight() = %v, want %v", weight, 2.5)
+	}
+	if label, _ := restored.GetEdgeLabel("2", "3"); label != "depends" {
+		t.Errorf("GetEdgeLabel() = %v, want %v", label, "depends")
+	}
+
+	// invalid
+	var wd testStorableDAG
+	_, err = UnmarshalYAML([]byte("vs: [{i: \"1\"}, {i: \"1\"}]"), &wd)
+	if err == nil {
+		t.Errorf("UnmarshalYAML() = nil, want error")
+	}
+}